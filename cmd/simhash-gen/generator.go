@@ -0,0 +1,485 @@
+/*
+   Copyright 2017 Joseph Cumines
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+
+ */
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/format"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const hashingPath = "github.com/joeycumines/go-hashmap/simhash/hashing"
+
+// keySource declares an interface identical to simhash.Key, which is used to check if types implement it, without
+// needing to import simhash.
+const keySource = `package key
+
+type Key interface {
+	Hash() int
+	Equals(other interface{}) bool
+}
+`
+
+// generator writes the methods for the struct types in a single package.
+type generator struct {
+	pkg     *types.Package
+	key     *types.Interface
+	targets map[string]bool
+	imports map[string]bool
+	buf     bytes.Buffer
+	vars    int
+	// err is the first error from type checking the package, if any, which is reported for fields of invalid types.
+	err error
+}
+
+// generate returns the formatted source of a file for the package in dir, containing Hash and Equals methods for
+// each of typeNames, where exclude is the name of a file to ignore, which will usually be the previous output.
+func generate(dir string, typeNames []string, exclude string) ([]byte, error) {
+	pkg, err := loadPackage(dir, exclude)
+	if nil == pkg {
+		return nil, err
+	}
+	g := &generator{
+		pkg:     pkg,
+		key:     keyInterface(),
+		targets: make(map[string]bool),
+		imports: map[string]bool{hashingPath: true},
+		err:     err,
+	}
+	for _, name := range typeNames {
+		g.targets[name] = true
+	}
+	for _, name := range typeNames {
+		if err := g.generateType(name); nil != err {
+			return nil, err
+		}
+	}
+	return g.source()
+}
+
+// loadPackage parses and type checks the package in dir. Type checking errors are expected, e.g. if other files
+// depend on the methods being generated, so the first will be returned along with the package, which will only be
+// nil if the package could not be parsed.
+func loadPackage(dir string, exclude string) (*types.Package, error) {
+	bp, err := build.ImportDir(dir, 0)
+	if nil != err {
+		return nil, err
+	}
+	fset := token.NewFileSet()
+	files := make([]*ast.File, 0, len(bp.GoFiles))
+	for _, name := range bp.GoFiles {
+		if exclude == name {
+			continue
+		}
+		f, err := parser.ParseFile(fset, filepath.Join(bp.Dir, name), nil, 0)
+		if nil != err {
+			return nil, err
+		}
+		files = append(files, f)
+	}
+	var first error
+	conf := types.Config{
+		Importer: importer.ForCompiler(fset, "source", nil),
+		Error: func(err error) {
+			if nil == first {
+				first = err
+			}
+		},
+	}
+	pkg, _ := conf.Check(bp.ImportPath, fset, files, nil)
+	return pkg, first
+}
+
+func keyInterface() *types.Interface {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "key.go", keySource, 0)
+	if nil != err {
+		panic(err)
+	}
+	pkg, err := new(types.Config).Check("key", fset, []*ast.File{f}, nil)
+	if nil != err {
+		panic(err)
+	}
+	return pkg.Scope().Lookup("Key").Type().Underlying().(*types.Interface)
+}
+
+func (g *generator) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&g.buf, format, args...)
+}
+
+// next returns a new suffix for variable names.
+func (g *generator) next() string {
+	g.vars++
+	return strconv.Itoa(g.vars)
+}
+
+func (g *generator) qualifier(pkg *types.Package) string {
+	if g.pkg == pkg {
+		return ""
+	}
+	return pkg.Name()
+}
+
+func (g *generator) typeString(t types.Type) string {
+	return types.TypeString(t, g.qualifier)
+}
+
+// fields returns the fields of s selected by their tags.
+func fields(s *types.Struct) ([]*types.Var, error) {
+	var all, included []*types.Var
+	for i := 0; i < s.NumFields(); i++ {
+		f := s.Field(i)
+		if "_" == f.Name() {
+			continue
+		}
+		switch tag, _ := reflect.StructTag(s.Tag(i)).Lookup("simhash"); tag {
+		case "":
+			all = append(all, f)
+		case "-":
+		case "include":
+			all = append(all, f)
+			included = append(included, f)
+		default:
+			return nil, fmt.Errorf("field %s has an invalid simhash tag %q", f.Name(), tag)
+		}
+	}
+	if 0 != len(included) {
+		return included, nil
+	}
+	return all, nil
+}
+
+func (g *generator) generateType(name string) error {
+	obj, ok := g.pkg.Scope().Lookup(name).(*types.TypeName)
+	if false == ok {
+		return fmt.Errorf("type %s not found in package %s", name, g.pkg.Path())
+	}
+	s, ok := obj.Type().Underlying().(*types.Struct)
+	if false == ok {
+		return fmt.Errorf("type %s is not a struct", name)
+	}
+	selected, err := fields(s)
+	if nil != err {
+		return fmt.Errorf("type %s: %s", name, err.Error())
+	}
+
+	g.printf("// Hash implements simhash.Key, combining the hashes of the fields of %s.\n", name)
+	g.printf("func (k %s) Hash() int {\n", name)
+	g.printf("h := hashing.NewHasher()\n")
+	for _, f := range selected {
+		if err := g.hash("h", "k."+f.Name(), f.Type()); nil != err {
+			return fmt.Errorf("type %s: field %s: %s", name, f.Name(), err.Error())
+		}
+	}
+	g.printf("return h.Hash()\n}\n\n")
+
+	g.printf("// Equals implements simhash.Key, returning true if other is a %s, or a non-nil *%s, with equal fields.\n", name, name)
+	g.printf("func (k %s) Equals(other interface{}) bool {\n", name)
+	g.printf("var o %s\n", name)
+	g.printf("switch v := other.(type) {\ncase %s:\no = v\n", name)
+	g.printf("case *%s:\nif nil == v {\nreturn false\n}\no = *v\n", name)
+	g.printf("default:\nreturn false\n}\n")
+	for _, f := range selected {
+		if err := g.equal("k."+f.Name(), "o."+f.Name(), f.Type()); nil != err {
+			return fmt.Errorf("type %s: field %s: %s", name, f.Name(), err.Error())
+		}
+	}
+	g.printf("return true\n}\n\n")
+	return nil
+}
+
+// isTarget returns true if t is one of the types being generated.
+func (g *generator) isTarget(t types.Type) bool {
+	n, ok := t.(*types.Named)
+	return ok && g.pkg == n.Obj().Pkg() && true == g.targets[n.Obj().Name()]
+}
+
+// keyKind returns if t implements simhash.Key, or if only a pointer to t does.
+func (g *generator) keyKind(t types.Type) (direct bool, pointer bool) {
+	// invalid types implement every interface
+	if b, ok := t.(*types.Basic); true == ok && types.Invalid == b.Kind() {
+		return false, false
+	}
+	if true == g.isTarget(t) {
+		return true, false
+	}
+	if p, ok := t.(*types.Pointer); true == ok {
+		if true == g.isTarget(p.Elem()) {
+			return true, false
+		}
+		// a pointer to a key with value receivers would be passed to an Equals expecting the element type, so the
+		// element is compared instead, as for any other pointer
+		if _, ok := p.Elem().Underlying().(*types.Interface); false == ok && true == types.Implements(p.Elem(), g.key) {
+			return false, false
+		}
+	}
+	if true == types.Implements(t, g.key) {
+		return true, false
+	}
+	switch t.Underlying().(type) {
+	case *types.Pointer, *types.Interface:
+		return false, false
+	}
+	return false, types.Implements(types.NewPointer(t), g.key)
+}
+
+// nillable returns true if a key of type t may be nil, and must not be called if it is.
+func nillable(t types.Type) bool {
+	switch t.Underlying().(type) {
+	case *types.Pointer, *types.Interface:
+		return true
+	}
+	return false
+}
+
+// mayHoldFloat returns true if values of type t may contain a float, which can't be used in the keys of map fields,
+// as the lookups used by Equals would never find a NaN key, so the map would not equal itself.
+func mayHoldFloat(t types.Type) bool {
+	switch u := t.Underlying().(type) {
+	case *types.Basic:
+		return 0 != u.Info()&(types.IsFloat|types.IsComplex)
+	case *types.Array:
+		return mayHoldFloat(u.Elem())
+	case *types.Struct:
+		for i := 0; i < u.NumFields(); i++ {
+			if true == mayHoldFloat(u.Field(i).Type()) {
+				return true
+			}
+		}
+	case *types.Interface:
+		return true
+	}
+	return false
+}
+
+func (g *generator) unsupported(t types.Type) error {
+	if b, ok := t.Underlying().(*types.Basic); true == ok && types.Invalid == b.Kind() && nil != g.err {
+		return fmt.Errorf("invalid type: %s", g.err.Error())
+	}
+	return fmt.Errorf("unsupported type %s, which does not implement simhash.Key, and must be excluded", g.typeString(t))
+}
+
+// convert returns x converted to basic, if t is a different type.
+func (g *generator) convert(basic string, x string, t types.Type) string {
+	if basic == g.typeString(t) {
+		return x
+	}
+	return basic + "(" + x + ")"
+}
+
+// index returns x indexed by i, where x may be a dereferenced pointer.
+func index(x, i string) string {
+	if strings.HasPrefix(x, "*") {
+		x = "(" + x + ")"
+	}
+	return x + "[" + i + "]"
+}
+
+// receiver returns x as the receiver of a method call, where x may be a dereferenced pointer.
+func receiver(x string) string {
+	if strings.HasPrefix(x, "*") {
+		return "(" + x + ")"
+	}
+	return x
+}
+
+// hash writes statements adding x, of type t, to the hasher named h.
+func (g *generator) hash(h, x string, t types.Type) error {
+	if direct, pointer := g.keyKind(t); true == direct {
+		g.printf("%s.Key(%s)\n", h, x)
+		return nil
+	} else if true == pointer {
+		g.printf("%s.Key(&%s)\n", h, x)
+		return nil
+	}
+	switch u := t.Underlying().(type) {
+	case *types.Basic:
+		info := u.Info()
+		switch {
+		case 0 != info&types.IsBoolean:
+			g.printf("%s.Bool(%s)\n", h, g.convert("bool", x, t))
+		case 0 != info&types.IsUnsigned:
+			g.printf("%s.Uint64(%s)\n", h, g.convert("uint64", x, t))
+		case 0 != info&types.IsInteger:
+			g.printf("%s.Int64(%s)\n", h, g.convert("int64", x, t))
+		case 0 != info&types.IsFloat:
+			g.printf("%s.Float64(%s)\n", h, g.convert("float64", x, t))
+		case 0 != info&types.IsString:
+			g.printf("%s.String(%s)\n", h, g.convert("string", x, t))
+		default:
+			return g.unsupported(t)
+		}
+	case *types.Pointer:
+		g.printf("if nil == %s {\n%s.Value(nil)\n} else {\n", x, h)
+		if err := g.hash(h, "*"+x, u.Elem()); nil != err {
+			return err
+		}
+		g.printf("}\n")
+	case *types.Slice:
+		if types.Identical(u.Elem(), types.Typ[types.Byte]) {
+			g.printf("%s.Bytes(%s)\n", h, x)
+			return nil
+		}
+		v := "v" + g.next()
+		g.printf("%s.Int(len(%s))\n", h, x)
+		g.printf("for _, %s := range %s {\n", v, x)
+		if err := g.hash(h, v, u.Elem()); nil != err {
+			return err
+		}
+		g.printf("}\n")
+	case *types.Array:
+		v := "v" + g.next()
+		g.printf("for _, %s := range %s {\n", v, x)
+		if err := g.hash(h, v, u.Elem()); nil != err {
+			return err
+		}
+		g.printf("}\n")
+	case *types.Map:
+		if true == mayHoldFloat(u.Key()) {
+			return fmt.Errorf("unsupported map key type %s, which may hold a float, that can't be compared using ==", g.typeString(u.Key()))
+		}
+		// the order of a map is random, so the entries are hashed individually, then summed
+		n := g.next()
+		s, k, v, eh := "s"+n, "k"+n, "v"+n, "h"+n
+		g.printf("var %s uint64\n", s)
+		g.printf("for %s, %s := range %s {\n", k, v, x)
+		g.printf("%s := hashing.NewHasher()\n", eh)
+		if err := g.hash(eh, k, u.Key()); nil != err {
+			return err
+		}
+		if err := g.hash(eh, v, u.Elem()); nil != err {
+			return err
+		}
+		g.printf("%s += %s.Hash64()\n}\n", s, eh)
+		g.printf("%s.Int(len(%s)).Uint64(%s)\n", h, x, s)
+	default:
+		return g.unsupported(t)
+	}
+	return nil
+}
+
+// equal writes statements that return false if a is not equal to b, of type t.
+func (g *generator) equal(a, b string, t types.Type) error {
+	if direct, pointer := g.keyKind(t); true == direct {
+		if true == nillable(t) {
+			g.printf("if (nil == %s) != (nil == %s) || (nil != %s && false == %s.Equals(%s)) {\nreturn false\n}\n", a, b, a, receiver(a), b)
+			return nil
+		}
+		g.printf("if false == %s.Equals(%s) {\nreturn false\n}\n", receiver(a), b)
+		return nil
+	} else if true == pointer {
+		g.printf("if false == (&%s).Equals(&%s) {\nreturn false\n}\n", a, b)
+		return nil
+	}
+	switch u := t.Underlying().(type) {
+	case *types.Basic:
+		info := u.Info()
+		switch {
+		case 0 != info&types.IsFloat:
+			// unequal, unless they are == with the same sign (as 0 == -0), or are both NaN
+			g.imports["math"] = true
+			g.printf("if (%s != %s || math.Signbit(float64(%s)) != math.Signbit(float64(%s))) && (%s == %s || %s == %s) {\nreturn false\n}\n", a, b, a, b, a, a, b, b)
+		case 0 != info&(types.IsBoolean|types.IsInteger|types.IsString):
+			g.printf("if %s != %s {\nreturn false\n}\n", a, b)
+		default:
+			return g.unsupported(t)
+		}
+	case *types.Pointer:
+		g.printf("if (nil == %s) != (nil == %s) {\nreturn false\n}\n", a, b)
+		g.printf("if nil != %s {\n", a)
+		if err := g.equal("*"+a, "*"+b, u.Elem()); nil != err {
+			return err
+		}
+		g.printf("}\n")
+	case *types.Slice:
+		if types.Identical(u.Elem(), types.Typ[types.Byte]) {
+			g.imports["bytes"] = true
+			g.printf("if false == bytes.Equal(%s, %s) {\nreturn false\n}\n", a, b)
+			return nil
+		}
+		i := "i" + g.next()
+		g.printf("if len(%s) != len(%s) {\nreturn false\n}\n", a, b)
+		g.printf("for %s := range %s {\n", i, a)
+		if err := g.equal(index(a, i), index(b, i), u.Elem()); nil != err {
+			return err
+		}
+		g.printf("}\n")
+	case *types.Array:
+		i := "i" + g.next()
+		g.printf("for %s := range %s {\n", i, a)
+		if err := g.equal(index(a, i), index(b, i), u.Elem()); nil != err {
+			return err
+		}
+		g.printf("}\n")
+	case *types.Map:
+		n := g.next()
+		k, v, w := "k"+n, "v"+n, "w"+n
+		g.printf("if len(%s) != len(%s) {\nreturn false\n}\n", a, b)
+		g.printf("for %s, %s := range %s {\n", k, v, a)
+		g.printf("%s, ok := %s\nif false == ok {\nreturn false\n}\n", w, index(b, k))
+		if err := g.equal(v, w, u.Elem()); nil != err {
+			return err
+		}
+		g.printf("}\n")
+	default:
+		return g.unsupported(t)
+	}
+	return nil
+}
+
+// source returns the complete, formatted, file.
+func (g *generator) source() ([]byte, error) {
+	var src bytes.Buffer
+	fmt.Fprintf(&src, "// Code generated by simhash-gen; DO NOT EDIT.\n\n")
+	fmt.Fprintf(&src, "package %s\n\n", g.pkg.Name())
+	imports := make([]string, 0, len(g.imports))
+	for path := range g.imports {
+		imports = append(imports, path)
+	}
+	sort.Strings(imports)
+	fmt.Fprintf(&src, "import (\n")
+	for _, path := range imports {
+		// the standard library is grouped first
+		if hashingPath == path {
+			continue
+		}
+		fmt.Fprintf(&src, "%q\n", path)
+	}
+	if 1 != len(imports) {
+		fmt.Fprintf(&src, "\n")
+	}
+	fmt.Fprintf(&src, "%q\n)\n\n", hashingPath)
+	src.Write(g.buf.Bytes())
+	out, err := format.Source(src.Bytes())
+	if nil != err {
+		return nil, errors.New("failed to format the generated source: " + err.Error())
+	}
+	return out, nil
+}
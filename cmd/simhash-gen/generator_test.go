@@ -0,0 +1,85 @@
+/*
+   Copyright 2017 Joseph Cumines
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+
+ */
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestGenerate_example checks that the generated methods for the example package are up to date, the behaviour of
+// which is tested by the example package itself.
+func TestGenerate_example(t *testing.T) {
+	dir := filepath.Join("internal", "example")
+	expected, err := os.ReadFile(filepath.Join(dir, "point_simhash.go"))
+	if nil != err {
+		t.Fatal(err)
+	}
+	actual, err := generate(dir, []string{"Point", "Shape", "Node", "Tagged"}, "point_simhash.go")
+	if nil != err {
+		t.Fatal(err)
+	}
+	if false == bytes.Equal(expected, actual) {
+		t.Fatalf("generated source differs, run go generate:\n%s", actual)
+	}
+}
+
+func TestGenerate_invalid(t *testing.T) {
+	dir := filepath.Join("testdata", "invalid")
+	for _, tc := range []struct {
+		typeName string
+		err      string
+	}{
+		{"Missing", "type Missing not found in package"},
+		{"NotStruct", "type NotStruct is not a struct"},
+		{"Channel", "type Channel: field C: unsupported type chan int"},
+		{"Complex", "type Complex: field C: unsupported type complex128"},
+		{"Interface", "type Interface: field V: unsupported type interface{}"},
+		{"Nested", "type Nested: field S: unsupported type struct{A int}"},
+		{"NestedMap", "type NestedMap: field M: unsupported type struct{}"},
+		{"FloatMap", "type FloatMap: field M: unsupported map key type float64, which may hold a float"},
+		{"FloatArrayMap", "type FloatArrayMap: field M: unsupported map key type [2]float32, which may hold a float"},
+		{"InterfaceMap", "type InterfaceMap: field M: unsupported map key type interface{String() string}, which may hold a float"},
+		{"BadTag", `type BadTag: field A has an invalid simhash tag "exclude"`},
+		{"Undefined", "type Undefined: field U: invalid type: "},
+	} {
+		_, err := generate(dir, []string{tc.typeName}, "")
+		if nil == err || false == strings.Contains(err.Error(), tc.err) {
+			t.Errorf("%s: unexpected error: %v", tc.typeName, err)
+		}
+	}
+	src, err := generate(dir, []string{"Excluded"}, "")
+	if nil != err || false == bytes.Contains(src, []byte("func (k Excluded) Hash() int {")) || true == bytes.Contains(src, []byte("k.C")) {
+		t.Fatalf("unexpected: %v\n%s", err, src)
+	}
+}
+
+func TestGenerate_noPackage(t *testing.T) {
+	if _, err := generate(filepath.Join("testdata", "missing"), []string{"T"}, ""); nil == err {
+		t.Fatal()
+	}
+}
+
+func TestIndex(t *testing.T) {
+	if "k.A[i]" != index("k.A", "i") || "(*k.A)[i]" != index("*k.A", "i") {
+		t.Fatal()
+	}
+}
@@ -0,0 +1,82 @@
+/*
+   Copyright 2017 Joseph Cumines
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+
+ */
+
+// Package example contains types using methods generated by simhash-gen, to test the generated code.
+package example
+
+//go:generate go run ../.. -type=Point,Shape,Node,Tagged
+
+import (
+	"github.com/joeycumines/go-hashmap/simhash"
+	"github.com/joeycumines/go-hashmap/simhash/keys"
+)
+
+type (
+	Celsius float64
+
+	Point struct {
+		X, Y int
+		Temp Celsius
+	}
+
+	// Shape uses every supported kind of field.
+	Shape struct {
+		Name     string
+		Visible  bool
+		Flags    uint8
+		Data     []byte
+		Origin   Point
+		Centre   *Point
+		Points   []Point
+		Corners  [4]int
+		Labels   map[string][]int
+		Key      simhash.Key
+		Named    keys.StringKey
+		Alias    *keys.StringKey
+		Counter  *counter
+		Children []*Shape
+		Scale    *float64
+	}
+
+	// Node is recursive, and excludes a field.
+	Node struct {
+		Value  int
+		Parent *Node
+		cache  int `simhash:"-"`
+	}
+
+	// Tagged only includes the tagged fields.
+	Tagged struct {
+		ID      int `simhash:"include"`
+		Version int `simhash:"include"`
+		Comment string
+	}
+
+	// counter only implements simhash.Key as a pointer.
+	counter struct {
+		n int
+	}
+)
+
+func (c *counter) Hash() int {
+	return c.n
+}
+
+func (c *counter) Equals(other interface{}) bool {
+	o, ok := other.(*counter)
+	return ok && nil != o && c.n == o.n
+}
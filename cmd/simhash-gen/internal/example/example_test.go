@@ -0,0 +1,170 @@
+/*
+   Copyright 2017 Joseph Cumines
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+
+ */
+
+package example
+
+import (
+	"math"
+	"testing"
+
+	"github.com/joeycumines/go-hashmap/simhash"
+	"github.com/joeycumines/go-hashmap/simhash/keys"
+)
+
+var (
+	_ simhash.Key = Point{}
+	_ simhash.Key = &Point{}
+	_ simhash.Key = Shape{}
+	_ simhash.Key = Node{}
+	_ simhash.Key = Tagged{}
+)
+
+func newShape() Shape {
+	scale := 1.5
+	alias := keys.StringKey("alias")
+	return Shape{
+		Name:     "square",
+		Visible:  true,
+		Flags:    3,
+		Data:     []byte{1, 2},
+		Origin:   Point{1, 2, 20},
+		Centre:   &Point{3, 4, 0},
+		Points:   []Point{{1, 1, 0}, {2, 2, 0}},
+		Corners:  [4]int{1, 2, 3, 4},
+		Labels:   map[string][]int{"a": {1}, "b": {2, 3}, "c": nil},
+		Key:      keys.IntKey(5),
+		Named:    "named",
+		Alias:    &alias,
+		Counter:  &counter{7},
+		Children: []*Shape{nil, {Name: "child"}},
+		Scale:    &scale,
+	}
+}
+
+// assertEqual checks that a and b are equal, in both directions, and have the same hash.
+func assertEqual(t *testing.T, a, b simhash.Key) {
+	t.Helper()
+	if false == a.Equals(b) || false == b.Equals(a) || a.Hash() != b.Hash() {
+		t.Fatalf("expected equal: %#v %#v", a, b)
+	}
+}
+
+func assertNotEqual(t *testing.T, a, b simhash.Key) {
+	t.Helper()
+	if true == a.Equals(b) || true == b.Equals(a) {
+		t.Fatalf("expected not equal: %#v %#v", a, b)
+	}
+}
+
+func TestPoint(t *testing.T) {
+	assertEqual(t, Point{1, 2, 3}, Point{1, 2, 3})
+	assertEqual(t, Point{1, 2, 3}, &Point{1, 2, 3})
+	assertEqual(t, Point{1, 2, Celsius(math.NaN())}, Point{1, 2, Celsius(math.NaN())})
+	assertNotEqual(t, Point{1, 2, 0}, Point{1, 2, Celsius(math.Copysign(0, -1))})
+	assertNotEqual(t, Point{1, 2, 3}, Point{2, 1, 3})
+	assertNotEqual(t, Point{1, 2, 3}, Point{1, 2, 4})
+	if true == (Point{}).Equals((*Point)(nil)) || true == (Point{}).Equals(nil) || true == (Point{}).Equals(Node{}) {
+		t.Fatal()
+	}
+	if (Point{1, 2, 3}).Hash() == (Point{2, 1, 3}).Hash() {
+		t.Fatal()
+	}
+}
+
+func TestShape(t *testing.T) {
+	a, b := newShape(), newShape()
+	assertEqual(t, a, a)
+	assertEqual(t, a, b)
+	assertEqual(t, a, &b)
+	for i, modify := range []func(s *Shape){
+		func(s *Shape) { s.Name = "circle" },
+		func(s *Shape) { s.Visible = false },
+		func(s *Shape) { s.Flags = 4 },
+		func(s *Shape) { s.Data = []byte{1} },
+		func(s *Shape) { s.Origin.X = 9 },
+		func(s *Shape) { s.Centre = nil },
+		func(s *Shape) { s.Centre = &Point{} },
+		func(s *Shape) { s.Points = s.Points[:1] },
+		func(s *Shape) { s.Points = []Point{{1, 1, 0}, {2, 3, 0}} },
+		func(s *Shape) { s.Corners[3] = 5 },
+		func(s *Shape) { s.Labels = map[string][]int{"a": {1}, "b": {2, 3}} },
+		func(s *Shape) { s.Labels = map[string][]int{"a": {1}, "b": {3, 2}, "c": nil} },
+		func(s *Shape) { s.Labels = map[string][]int{"a": {1}, "b": {2, 3}, "d": nil} },
+		func(s *Shape) { s.Key = nil },
+		func(s *Shape) { s.Key = keys.StringKey("5") },
+		func(s *Shape) { s.Named = "other" },
+		func(s *Shape) { s.Alias = nil },
+		func(s *Shape) { v := keys.StringKey("other"); s.Alias = &v },
+		func(s *Shape) { s.Counter = nil },
+		func(s *Shape) { s.Counter = &counter{8} },
+		func(s *Shape) { s.Children = s.Children[1:] },
+		func(s *Shape) { s.Children = []*Shape{{}, {Name: "child"}} },
+		func(s *Shape) { s.Scale = nil },
+		func(s *Shape) { v := -1.5; s.Scale = &v },
+	} {
+		c := newShape()
+		modify(&c)
+		if true == a.Equals(c) || true == c.Equals(a) {
+			t.Errorf("%d: expected not equal", i)
+		}
+		if a.Hash() == c.Hash() {
+			t.Errorf("%d: expected a different hash", i)
+		}
+	}
+	// nil and empty slices and maps are equal
+	c, d := Shape{}, Shape{Data: []byte{}, Points: []Point{}, Labels: map[string][]int{}, Children: []*Shape{}}
+	assertEqual(t, c, d)
+}
+
+func TestShape_mapOrder(t *testing.T) {
+	a, b := newShape(), newShape()
+	a.Labels, b.Labels = make(map[string][]int), make(map[string][]int)
+	for i := 0; i < 100; i++ {
+		a.Labels[string(rune('a'+i))] = []int{i}
+		b.Labels[string(rune('a'+99-i))] = []int{99 - i}
+	}
+	assertEqual(t, a, b)
+}
+
+func TestNode(t *testing.T) {
+	root := &Node{Value: 1}
+	a := Node{Value: 2, Parent: root, cache: 1}
+	b := Node{Value: 2, Parent: &Node{Value: 1}, cache: 2}
+	assertEqual(t, a, b)
+	assertNotEqual(t, a, Node{Value: 2})
+	assertNotEqual(t, a, Node{Value: 2, Parent: &Node{Value: 3}})
+}
+
+func TestTagged(t *testing.T) {
+	assertEqual(t, Tagged{1, 2, "a"}, Tagged{1, 2, "b"})
+	assertNotEqual(t, Tagged{1, 2, "a"}, Tagged{1, 3, "a"})
+}
+
+func TestMap(t *testing.T) {
+	m := simhash.NewMap()
+	for i := 0; i < 10; i++ {
+		s := newShape()
+		s.Origin.X = i
+		m.Put(s, i)
+		m.Put(&Point{i, i, 0}, i)
+	}
+	s := newShape()
+	s.Origin.X = 5
+	if 20 != m.Size() || 5 != m.Get(s).(int) || 5 != m.Get(Point{5, 5, 0}).(int) {
+		t.Fatal(m.Size())
+	}
+}
@@ -0,0 +1,252 @@
+// Code generated by simhash-gen; DO NOT EDIT.
+
+package example
+
+import (
+	"bytes"
+	"math"
+
+	"github.com/joeycumines/go-hashmap/simhash/hashing"
+)
+
+// Hash implements simhash.Key, combining the hashes of the fields of Point.
+func (k Point) Hash() int {
+	h := hashing.NewHasher()
+	h.Int64(int64(k.X))
+	h.Int64(int64(k.Y))
+	h.Float64(float64(k.Temp))
+	return h.Hash()
+}
+
+// Equals implements simhash.Key, returning true if other is a Point, or a non-nil *Point, with equal fields.
+func (k Point) Equals(other interface{}) bool {
+	var o Point
+	switch v := other.(type) {
+	case Point:
+		o = v
+	case *Point:
+		if nil == v {
+			return false
+		}
+		o = *v
+	default:
+		return false
+	}
+	if k.X != o.X {
+		return false
+	}
+	if k.Y != o.Y {
+		return false
+	}
+	if (k.Temp != o.Temp || math.Signbit(float64(k.Temp)) != math.Signbit(float64(o.Temp))) && (k.Temp == k.Temp || o.Temp == o.Temp) {
+		return false
+	}
+	return true
+}
+
+// Hash implements simhash.Key, combining the hashes of the fields of Shape.
+func (k Shape) Hash() int {
+	h := hashing.NewHasher()
+	h.String(k.Name)
+	h.Bool(k.Visible)
+	h.Uint64(uint64(k.Flags))
+	h.Bytes(k.Data)
+	h.Key(k.Origin)
+	h.Key(k.Centre)
+	h.Int(len(k.Points))
+	for _, v1 := range k.Points {
+		h.Key(v1)
+	}
+	for _, v2 := range k.Corners {
+		h.Int64(int64(v2))
+	}
+	var s3 uint64
+	for k3, v3 := range k.Labels {
+		h3 := hashing.NewHasher()
+		h3.String(k3)
+		h3.Int(len(v3))
+		for _, v4 := range v3 {
+			h3.Int64(int64(v4))
+		}
+		s3 += h3.Hash64()
+	}
+	h.Int(len(k.Labels)).Uint64(s3)
+	h.Key(k.Key)
+	h.Key(k.Named)
+	if nil == k.Alias {
+		h.Value(nil)
+	} else {
+		h.Key(*k.Alias)
+	}
+	h.Key(k.Counter)
+	h.Int(len(k.Children))
+	for _, v5 := range k.Children {
+		h.Key(v5)
+	}
+	if nil == k.Scale {
+		h.Value(nil)
+	} else {
+		h.Float64(*k.Scale)
+	}
+	return h.Hash()
+}
+
+// Equals implements simhash.Key, returning true if other is a Shape, or a non-nil *Shape, with equal fields.
+func (k Shape) Equals(other interface{}) bool {
+	var o Shape
+	switch v := other.(type) {
+	case Shape:
+		o = v
+	case *Shape:
+		if nil == v {
+			return false
+		}
+		o = *v
+	default:
+		return false
+	}
+	if k.Name != o.Name {
+		return false
+	}
+	if k.Visible != o.Visible {
+		return false
+	}
+	if k.Flags != o.Flags {
+		return false
+	}
+	if false == bytes.Equal(k.Data, o.Data) {
+		return false
+	}
+	if false == k.Origin.Equals(o.Origin) {
+		return false
+	}
+	if (nil == k.Centre) != (nil == o.Centre) || (nil != k.Centre && false == k.Centre.Equals(o.Centre)) {
+		return false
+	}
+	if len(k.Points) != len(o.Points) {
+		return false
+	}
+	for i6 := range k.Points {
+		if false == k.Points[i6].Equals(o.Points[i6]) {
+			return false
+		}
+	}
+	for i7 := range k.Corners {
+		if k.Corners[i7] != o.Corners[i7] {
+			return false
+		}
+	}
+	if len(k.Labels) != len(o.Labels) {
+		return false
+	}
+	for k8, v8 := range k.Labels {
+		w8, ok := o.Labels[k8]
+		if false == ok {
+			return false
+		}
+		if len(v8) != len(w8) {
+			return false
+		}
+		for i9 := range v8 {
+			if v8[i9] != w8[i9] {
+				return false
+			}
+		}
+	}
+	if (nil == k.Key) != (nil == o.Key) || (nil != k.Key && false == k.Key.Equals(o.Key)) {
+		return false
+	}
+	if false == k.Named.Equals(o.Named) {
+		return false
+	}
+	if (nil == k.Alias) != (nil == o.Alias) {
+		return false
+	}
+	if nil != k.Alias {
+		if false == (*k.Alias).Equals(*o.Alias) {
+			return false
+		}
+	}
+	if (nil == k.Counter) != (nil == o.Counter) || (nil != k.Counter && false == k.Counter.Equals(o.Counter)) {
+		return false
+	}
+	if len(k.Children) != len(o.Children) {
+		return false
+	}
+	for i10 := range k.Children {
+		if (nil == k.Children[i10]) != (nil == o.Children[i10]) || (nil != k.Children[i10] && false == k.Children[i10].Equals(o.Children[i10])) {
+			return false
+		}
+	}
+	if (nil == k.Scale) != (nil == o.Scale) {
+		return false
+	}
+	if nil != k.Scale {
+		if (*k.Scale != *o.Scale || math.Signbit(float64(*k.Scale)) != math.Signbit(float64(*o.Scale))) && (*k.Scale == *k.Scale || *o.Scale == *o.Scale) {
+			return false
+		}
+	}
+	return true
+}
+
+// Hash implements simhash.Key, combining the hashes of the fields of Node.
+func (k Node) Hash() int {
+	h := hashing.NewHasher()
+	h.Int64(int64(k.Value))
+	h.Key(k.Parent)
+	return h.Hash()
+}
+
+// Equals implements simhash.Key, returning true if other is a Node, or a non-nil *Node, with equal fields.
+func (k Node) Equals(other interface{}) bool {
+	var o Node
+	switch v := other.(type) {
+	case Node:
+		o = v
+	case *Node:
+		if nil == v {
+			return false
+		}
+		o = *v
+	default:
+		return false
+	}
+	if k.Value != o.Value {
+		return false
+	}
+	if (nil == k.Parent) != (nil == o.Parent) || (nil != k.Parent && false == k.Parent.Equals(o.Parent)) {
+		return false
+	}
+	return true
+}
+
+// Hash implements simhash.Key, combining the hashes of the fields of Tagged.
+func (k Tagged) Hash() int {
+	h := hashing.NewHasher()
+	h.Int64(int64(k.ID))
+	h.Int64(int64(k.Version))
+	return h.Hash()
+}
+
+// Equals implements simhash.Key, returning true if other is a Tagged, or a non-nil *Tagged, with equal fields.
+func (k Tagged) Equals(other interface{}) bool {
+	var o Tagged
+	switch v := other.(type) {
+	case Tagged:
+		o = v
+	case *Tagged:
+		if nil == v {
+			return false
+		}
+		o = *v
+	default:
+		return false
+	}
+	if k.ID != o.ID {
+		return false
+	}
+	if k.Version != o.Version {
+		return false
+	}
+	return true
+}
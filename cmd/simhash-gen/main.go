@@ -0,0 +1,89 @@
+/*
+   Copyright 2017 Joseph Cumines
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+
+ */
+
+// Command simhash-gen generates Hash and Equals methods for struct types, implementing simhash.Key, intended to be
+// used with go:generate, e.g.
+//
+//	//go:generate simhash-gen -type=Point,Line
+//
+// The generated Hash combines the hashes of each field using the hashing package, and the generated Equals compares
+// each field, accepting either a value or a non-nil pointer of the same type. Both methods use value receivers, so
+// that the type implements simhash.Key as both a value and a pointer.
+//
+// Fields may be any of the following, or a named type with one of them as it's underlying type:
+//
+//   - booleans, integers, floats and strings, where floats follow the semantics of Java's Double.equals
+//   - types implementing simhash.Key, including interfaces, and the other types being generated
+//   - pointers, slices and arrays of supported types, compared by their elements
+//   - maps from comparable keys to supported types, compared using == for their keys, which must not contain floats,
+//     or interfaces, which may hold floats, as a NaN key can't be found, so the map would not equal itself
+//
+// Fields are selected using the `simhash` struct tag, where `simhash:"-"` excludes a field, and if any field is
+// tagged `simhash:"include"`, only the tagged fields will be used. Unsupported fields must be excluded.
+//
+// Usage:
+//
+//	simhash-gen [flags] [directory]
+//
+// Where directory defaults to the current directory, and the flags are:
+//
+//	-type string
+//		comma-separated list of struct type names; must be set
+//	-output string
+//		output file name; default <directory>/<type>_simhash.go, using the first type, in lower case
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func main() {
+	var (
+		typeNames = flag.String("type", "", "comma-separated list of struct type names; must be set")
+		output    = flag.String("output", "", "output file name; default <directory>/<type>_simhash.go")
+	)
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage of simhash-gen:\n\tsimhash-gen [flags] -type T [directory]\n\nFlags:\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if "" == *typeNames || flag.NArg() > 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	dir := "."
+	if 1 == flag.NArg() {
+		dir = flag.Arg(0)
+	}
+	types := strings.Split(*typeNames, ",")
+	if "" == *output {
+		*output = filepath.Join(dir, strings.ToLower(types[0])+"_simhash.go")
+	}
+	src, err := generate(dir, types, filepath.Base(*output))
+	if nil != err {
+		fmt.Fprintf(os.Stderr, "simhash-gen: %s\n", err.Error())
+		os.Exit(1)
+	}
+	if err := os.WriteFile(*output, src, 0644); nil != err {
+		fmt.Fprintf(os.Stderr, "simhash-gen: %s\n", err.Error())
+		os.Exit(1)
+	}
+}
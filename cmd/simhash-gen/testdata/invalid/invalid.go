@@ -0,0 +1,71 @@
+/*
+   Copyright 2017 Joseph Cumines
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+
+ */
+
+// Package invalid contains types which simhash-gen cannot generate methods for.
+package invalid
+
+type (
+	NotStruct int
+
+	Channel struct {
+		C chan int
+	}
+
+	Complex struct {
+		C complex128
+	}
+
+	Interface struct {
+		V interface{}
+	}
+
+	Nested struct {
+		S struct {
+			A int
+		}
+	}
+
+	NestedMap struct {
+		M map[string]struct{}
+	}
+
+	FloatMap struct {
+		M map[float64]int
+	}
+
+	FloatArrayMap struct {
+		M map[[2]float32]int
+	}
+
+	InterfaceMap struct {
+		M map[interface{ String() string }]int
+	}
+
+	BadTag struct {
+		A int `simhash:"exclude"`
+	}
+
+	Undefined struct {
+		U undefined.Type
+	}
+
+	// Excluded is valid, as the unsupported field is excluded.
+	Excluded struct {
+		A int
+		C chan int `simhash:"-"`
+	}
+)
@@ -0,0 +1,147 @@
+/*
+   Copyright 2017 Joseph Cumines
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+
+ */
+
+package simhash
+
+import (
+	"errors"
+)
+
+// BiMap is a Map that preserves the uniqueness of it's values as well as it's keys, the same as Guava's BiMap,
+// allowing lookup in either direction. All values must implement Key (or be nil), and Put will panic if given any
+// other value, or a value that is already bound to a different key.
+type BiMap interface {
+	Map
+
+	// ForcePut behaves the same as Put, except that any pair that already has value will be silently removed first,
+	// rather than causing a panic.
+	ForcePut(key Key, value Key) Value
+
+	// Inverse returns a view of this map with the keys and values swapped, which is backed by this map, so changes
+	// to either will be visible in both.
+	Inverse() BiMap
+}
+
+// biMap implements BiMap using a pair of hashMaps, one for each direction, which are shared with it's inverse.
+// It has the same concurrency restrictions as hashMap.
+type biMap struct {
+	forward  *hashMap
+	backward *hashMap
+	inverse  *biMap
+}
+
+func keysEqual(a, b Key) bool {
+	return (nil == a && nil == b) || (nil != a && nil != b && a.Equals(b))
+}
+
+func valueAsKey(value Value) Key {
+	if nil == value {
+		return nil
+	}
+	key, ok := value.(Key)
+	if false == ok {
+		panic(errors.New("the values of a bimap must implement Key"))
+	}
+	return key
+}
+
+func (m *biMap) put(key Key, value Value, force bool) Value {
+	valueKey := valueAsKey(value)
+	if h, i, ok := m.backward.lookup(valueKey); true == ok {
+		if existing := m.backward.m[h][i].Value().(Key); false == keysEqual(existing, key) {
+			if false == force {
+				panic(errors.New("the value is already bound to a different key in the bimap"))
+			}
+			m.backward.removeAt(h, i)
+			m.forward.Remove(existing)
+		}
+	}
+	h := m.forward.hash(key)
+	if i, ok := m.forward.find(h, key); true == ok {
+		old := m.forward.m[h][i].Value()
+		m.backward.Remove(valueAsKey(old))
+		m.forward.replaceAt(h, i, NewPair(key, value))
+		m.backward.Put(valueKey, key)
+		return old
+	}
+	m.forward.insert(h, NewPair(key, value))
+	m.backward.Put(valueKey, key)
+	return nil
+}
+
+func (m *biMap) Contains(key Key) bool {
+	return m.forward.Contains(key)
+}
+
+func (m *biMap) Get(key Key) Value {
+	return m.forward.Get(key)
+}
+
+func (m *biMap) Put(key Key, value Value) Value {
+	return m.put(key, value, false)
+}
+
+func (m *biMap) ForcePut(key Key, value Key) Value {
+	return m.put(key, value, true)
+}
+
+func (m *biMap) Remove(key Key) Value {
+	h, i, ok := m.forward.lookup(key)
+	if false == ok {
+		return nil
+	}
+	value := m.forward.removeAt(h, i)
+	m.backward.Remove(valueAsKey(value))
+	return value
+}
+
+func (m *biMap) Keys() []Key {
+	return m.forward.Keys()
+}
+
+func (m *biMap) Values() []Value {
+	return m.forward.Values()
+}
+
+func (m *biMap) Pairs() []Pair {
+	return m.forward.Pairs()
+}
+
+func (m *biMap) Size() int {
+	return m.forward.Size()
+}
+
+func (m *biMap) Serialize() map[string]interface{} {
+	return m.forward.Serialize()
+}
+
+// Iterator is not a MutableIterator, as modifying the forward map alone would break the inverse.
+func (m *biMap) Iterator() Iterator {
+	return readOnlyIterator{m.forward.Iterator()}
+}
+
+func (m *biMap) Inverse() BiMap {
+	return m.inverse
+}
+
+// NewBiMap creates a new, empty, BiMap. It is not safe for concurrent use.
+func NewBiMap() BiMap {
+	forward, backward := NewMap().(*hashMap), NewMap().(*hashMap)
+	m := &biMap{forward: forward, backward: backward}
+	m.inverse = &biMap{backward, forward, m}
+	return m
+}
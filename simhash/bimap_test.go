@@ -0,0 +1,134 @@
+/*
+   Copyright 2017 Joseph Cumines
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+
+ */
+
+package simhash
+
+import (
+	"testing"
+)
+
+func TestNewBiMap(t *testing.T) {
+	m := NewBiMap().(*biMap)
+	if m.inverse.inverse != m || m.forward != m.inverse.backward || m.backward != m.inverse.forward || 0 != m.Size() {
+		t.Fatal()
+	}
+	if m.Inverse() != m.inverse || m.Inverse().Inverse() != m {
+		t.Fatal()
+	}
+}
+
+func TestBiMap_Put(t *testing.T) {
+	m := NewBiMap()
+	inv := m.Inverse()
+	if nil != m.Put(testKeyInt(1), testKeyStruct{1, 10}) || nil != m.Put(testKeyInt(2), testKeyStruct{2, 20}) || nil != m.Put(nil, nil) {
+		t.Fatal()
+	}
+	if 3 != m.Size() || 3 != inv.Size() || testKeyInt(1) != inv.Get(testKeyStruct{1, 10}) || nil != inv.Get(nil) || true != inv.Contains(nil) {
+		t.Fatal()
+	}
+	// replacing the value for a key
+	if (testKeyStruct{1, 10}) != m.Put(testKeyInt(1), testKeyStruct{1, 11}) {
+		t.Fatal()
+	}
+	if 3 != inv.Size() || true == inv.Contains(testKeyStruct{1, 10}) || testKeyInt(1) != inv.Get(testKeyStruct{1, 11}) {
+		t.Fatal()
+	}
+	// putting the same pair again is fine
+	if (testKeyStruct{1, 11}) != m.Put(testKeyInt(1), testKeyStruct{1, 11}) || 3 != inv.Size() {
+		t.Fatal()
+	}
+	// replacing a nil value
+	if nil != m.Put(nil, testKeyInt(0)) || true == inv.Contains(nil) || nil != inv.Get(testKeyInt(0)) || true != inv.Contains(testKeyInt(0)) {
+		t.Fatal()
+	}
+	if testKeyInt(0) != m.Remove(nil) || 2 != inv.Size() {
+		t.Fatal()
+	}
+	m.Put(nil, nil)
+	if nil != m.Remove(nil) || 2 != inv.Size() {
+		t.Fatal()
+	}
+	m.Put(nil, nil)
+	// updates via the inverse
+	if nil != inv.Put(testKeyStruct{3, 30}, testKeyInt(3)) || (testKeyStruct{3, 30}) != m.Get(testKeyInt(3)) || 4 != m.Size() || 4 != inv.Size() {
+		t.Fatal()
+	}
+}
+
+func TestBiMap_Put_duplicateValue(t *testing.T) {
+	m := NewBiMap()
+	m.Put(testKeyInt(1), testKeyInt(10))
+	defer func() {
+		err := recover().(error)
+		if nil == err || "the value is already bound to a different key in the bimap" != err.Error() {
+			t.Fatal()
+		}
+		if 1 != m.Size() || testKeyInt(10) != m.Get(testKeyInt(1)) {
+			t.Fatal()
+		}
+	}()
+	m.Put(testKeyInt(2), testKeyInt(10))
+}
+
+func TestBiMap_Put_notKey(t *testing.T) {
+	defer func() {
+		err := recover().(error)
+		if nil == err || "the values of a bimap must implement Key" != err.Error() {
+			t.Fatal()
+		}
+	}()
+	NewBiMap().Put(testKeyInt(1), "one")
+}
+
+func TestBiMap_ForcePut(t *testing.T) {
+	m := NewBiMap()
+	m.Put(testKeyInt(1), testKeyInt(10))
+	m.Put(testKeyInt(2), testKeyInt(20))
+	if testKeyInt(20) != m.ForcePut(testKeyInt(2), testKeyInt(10)) {
+		t.Fatal()
+	}
+	if 1 != m.Size() || 1 != m.Inverse().Size() || true == m.Contains(testKeyInt(1)) || testKeyInt(2) != m.Inverse().Get(testKeyInt(10)) {
+		t.Fatal()
+	}
+	if nil != m.ForcePut(testKeyInt(3), testKeyInt(30)) || 2 != m.Size() {
+		t.Fatal()
+	}
+}
+
+func TestBiMap_Remove(t *testing.T) {
+	m := NewBiMap()
+	m.Put(testKeyInt(1), testKeyInt(10))
+	m.Put(testKeyInt(2), testKeyInt(20))
+	if nil != m.Remove(testKeyInt(3)) || testKeyInt(10) != m.Remove(testKeyInt(1)) || 1 != m.Inverse().Size() || true == m.Inverse().Contains(testKeyInt(10)) {
+		t.Fatal()
+	}
+	if testKeyInt(2) != m.Inverse().Remove(testKeyInt(20)) || 0 != m.Size() {
+		t.Fatal()
+	}
+}
+
+func TestBiMap_views(t *testing.T) {
+	m := NewBiMap()
+	m.Put(testKeyStruct{1, 1}, testKeyStruct{10, 10})
+	if 1 != len(m.Keys()) || 1 != len(m.Values()) || 1 != len(m.Pairs()) || 1 != m.Inverse().Serialize()["10"].(testKeyStruct).hash {
+		t.Fatal()
+	}
+	it := m.Inverse().Iterator()
+	if true != it.Next() || (testKeyStruct{10, 10}) != it.Key() || (testKeyStruct{1, 1}) != it.Value() || false != it.Next() {
+		t.Fatal()
+	}
+}
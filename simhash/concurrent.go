@@ -0,0 +1,187 @@
+/*
+   Copyright 2017 Joseph Cumines
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+
+ */
+
+package simhash
+
+import (
+	"sync"
+
+	"github.com/joeycumines/go-hashmap/simhash/internal/hashbits"
+)
+
+// concurrentShardCount is the number of lock-striped shards in a concurrentMap, it must be a power of two.
+const concurrentShardCount = 32
+
+// concurrentMap is a thread-safe Map, which partitions its pairs across a fixed number of hashMap shards, each
+// guarded by it's own lock, selected using the hash of the key. Operations on a single key only ever lock a single
+// shard, so readers and writers working with unrelated keys will usually not contend.
+// Operations that span the whole map (Keys, Values, Pairs, Serialize, Iterator) lock each shard in turn, and are
+// therefore only consistent per-shard, if the map is being concurrently modified.
+type concurrentMap struct {
+	shards []*concurrentShard
+	// seed is mixed into the hash of each key to select it's shard, if seeded, and is derived from the seed in the
+	// options, but differs from the seed of the shards, as every key in a shard would otherwise share the low bits
+	seed   uint64
+	seeded bool
+}
+
+type concurrentShard struct {
+	mutex sync.RWMutex
+	m     ComputeMap
+}
+
+func (m *concurrentMap) shard(key Key) *concurrentShard {
+	u := hash64Of(key)
+	if true == m.seeded {
+		u = hashbits.Mix(u ^ m.seed)
+	} else {
+		// spread the higher bits down, so keys with hashes that only differ in the upper bits don't share a shard
+		u ^= u >> 32
+		u ^= u >> 16
+		u ^= u >> 8
+	}
+	return m.shards[u&uint64(len(m.shards)-1)]
+}
+
+func (m *concurrentMap) Contains(key Key) bool {
+	s := m.shard(key)
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.m.Contains(key)
+}
+
+func (m *concurrentMap) Get(key Key) Value {
+	s := m.shard(key)
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.m.Get(key)
+}
+
+func (m *concurrentMap) Put(key Key, value Value) Value {
+	s := m.shard(key)
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.m.Put(key, value)
+}
+
+func (m *concurrentMap) Remove(key Key) Value {
+	s := m.shard(key)
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.m.Remove(key)
+}
+
+func (m *concurrentMap) ComputeIfAbsent(key Key, fn func(key Key) Value) Value {
+	s := m.shard(key)
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.m.ComputeIfAbsent(key, fn)
+}
+
+func (m *concurrentMap) ComputeIfPresent(key Key, fn func(key Key, value Value) Value) Value {
+	s := m.shard(key)
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.m.ComputeIfPresent(key, fn)
+}
+
+func (m *concurrentMap) Compute(key Key, fn func(key Key, value Value) Value) Value {
+	s := m.shard(key)
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.m.Compute(key, fn)
+}
+
+func (m *concurrentMap) Merge(key Key, value Value, fn func(old, new Value) Value) Value {
+	s := m.shard(key)
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.m.Merge(key, value, fn)
+}
+
+func (m *concurrentMap) Keys() []Key {
+	keys := make([]Key, 0)
+	for _, s := range m.shards {
+		s.mutex.RLock()
+		keys = append(keys, s.m.Keys()...)
+		s.mutex.RUnlock()
+	}
+	return keys
+}
+
+func (m *concurrentMap) Values() []Value {
+	values := make([]Value, 0)
+	for _, s := range m.shards {
+		s.mutex.RLock()
+		values = append(values, s.m.Values()...)
+		s.mutex.RUnlock()
+	}
+	return values
+}
+
+func (m *concurrentMap) Pairs() []Pair {
+	pairs := make([]Pair, 0)
+	for _, s := range m.shards {
+		s.mutex.RLock()
+		pairs = append(pairs, s.m.Pairs()...)
+		s.mutex.RUnlock()
+	}
+	return pairs
+}
+
+func (m *concurrentMap) Size() int {
+	size := 0
+	for _, s := range m.shards {
+		s.mutex.RLock()
+		size += s.m.Size()
+		s.mutex.RUnlock()
+	}
+	return size
+}
+
+func (m *concurrentMap) Serialize() map[string]interface{} {
+	serialized := make(map[string]interface{})
+	for _, s := range m.shards {
+		s.mutex.RLock()
+		for k, v := range s.m.Serialize() {
+			serialized[k] = v
+		}
+		s.mutex.RUnlock()
+	}
+	return serialized
+}
+
+// Iterator returns an iterator over a snapshot of the pairs in the map, taken at the time of the call, which is
+// therefore safe to use while the map is being modified. Remove and SetValue are the same as calling Remove or Put on
+// the map, and are each atomic.
+func (m *concurrentMap) Iterator() Iterator {
+	return newSnapshotIterator(m, m.Pairs())
+}
+
+// NewConcurrentMap creates a Map that is safe for concurrent use by multiple goroutines, without any additional
+// locking or coordination. The returned map also implements ComputeMap, with each operation holding the lock for the
+// key for the duration of the call, so the provided functions must not access the map, even to read a different key,
+// which may share the lock. Each shard is created by NewMap, using options, and the shard for each key is selected
+// using a seed derived from the hash seed in options, if there is one.
+func NewConcurrentMap(options ...Option) Map {
+	shards := make([]*concurrentShard, concurrentShardCount)
+	for i := range shards {
+		shards[i] = &concurrentShard{m: NewMap(options...).(ComputeMap)}
+	}
+	o := newMapOptions(options)
+	return &concurrentMap{shards: shards, seed: hashbits.Mix(o.seed ^ hashbits.Offset), seeded: o.seeded}
+}
@@ -0,0 +1,299 @@
+/*
+   Copyright 2017 Joseph Cumines
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+
+ */
+
+package simhash
+
+import (
+	"sort"
+	"sync"
+	"testing"
+)
+
+func genTestStructureConcurrentMap() Map {
+	m := NewConcurrentMap()
+	for x := 1; x <= 3; x++ {
+		for y := 1; y <= 3; y++ {
+			i := x*10 + y
+			m.Put(testKeyStruct{x, i}, i)
+		}
+	}
+	m.Put(nil, 0)
+	return m
+}
+
+func TestNewConcurrentMap(t *testing.T) {
+	m := NewConcurrentMap().(*concurrentMap)
+	if concurrentShardCount != len(m.shards) || 0 != m.Size() {
+		t.Fatal()
+	}
+	for _, s := range m.shards {
+		if nil == s || nil == s.m || 0 != s.m.Size() {
+			t.Fatal()
+		}
+	}
+}
+
+func TestNewConcurrentMap_options(t *testing.T) {
+	m := NewConcurrentMap(WithHashSeed(7)).(*concurrentMap)
+	for _, s := range m.shards {
+		if h := s.m.(*hashMap); true != h.seeded || 7 != h.seed {
+			t.Fatal()
+		}
+	}
+	m = NewConcurrentMap(WithOpenAddressing()).(*concurrentMap)
+	for _, s := range m.shards {
+		if _, ok := s.m.(*swissMap); true != ok {
+			t.Fatal()
+		}
+	}
+	m.Put(testKeyInt(1), 1)
+	if 1 != m.Get(testKeyInt(1)).(int) || 1 != m.Size() {
+		t.Fatal()
+	}
+}
+
+func TestConcurrentMap_shard(t *testing.T) {
+	m := NewConcurrentMap(WithoutHashSeed()).(*concurrentMap)
+	if m.shard(nil) != m.shard(testKeyInt(0)) {
+		t.Fatal()
+	}
+	if m.shard(testKeyInt(1)) == m.shard(testKeyInt(2)) {
+		t.Fatal()
+	}
+	if m.shard(testKeyInt(1<<16)) == m.shard(testKeyInt(2<<16)) {
+		t.Fatal()
+	}
+}
+
+func TestConcurrentMap_shard_seeded(t *testing.T) {
+	shards := func(m Map) []*concurrentShard {
+		s := make([]*concurrentShard, 0, 100)
+		for i := 0; i < 100; i++ {
+			s = append(s, m.(*concurrentMap).shard(testKeyInt(i)))
+		}
+		return s
+	}
+	indexes := func(m Map) []int {
+		idx := make([]int, 0, 100)
+		for _, s := range shards(m) {
+			for i, o := range m.(*concurrentMap).shards {
+				if s == o {
+					idx = append(idx, i)
+				}
+			}
+		}
+		return idx
+	}
+	a, b, c := indexes(NewConcurrentMap(WithHashSeed(1))), indexes(NewConcurrentMap(WithHashSeed(1))), indexes(NewConcurrentMap(WithHashSeed(2)))
+	if false == equalInts(a, b) || true == equalInts(a, c) || true == equalInts(a, indexes(NewConcurrentMap())) {
+		t.Fatal(a, c)
+	}
+	// the keys in each shard don't share the low bits of their hash in the shard
+	m := NewConcurrentMap(WithHashSeed(1), WithOpenAddressing()).(*concurrentMap)
+	bits := make(map[uint64]bool)
+	for i := 0; i < 100; i++ {
+		if s := m.shard(testKeyInt(i)); s == m.shards[0] {
+			bits[s.m.(*swissMap).hash(testKeyInt(i))&31] = true
+		}
+	}
+	if len(bits) < 2 {
+		t.Fatal(bits)
+	}
+}
+
+func TestConcurrentMap_Put(t *testing.T) {
+	m := NewConcurrentMap()
+	if nil != m.Put(testKeyInt(67), "67") || 1 != m.Size() || "67" != m.Get(testKeyInt(67)).(string) {
+		t.Fatal()
+	}
+	if "67" != m.Put(testKeyInt(67), "sixty seven").(string) || 1 != m.Size() || "sixty seven" != m.Get(testKeyInt(67)).(string) {
+		t.Fatal()
+	}
+	if nil != m.Put(testKeyInt(68), "68") || 2 != m.Size() || "68" != m.Get(testKeyInt(68)).(string) {
+		t.Fatal()
+	}
+	if nil != m.Put(nil, "val") || 3 != m.Size() || "val" != m.Get(nil) || true != m.Contains(nil) {
+		t.Fatal()
+	}
+}
+
+func TestConcurrentMap_Remove(t *testing.T) {
+	m := NewConcurrentMap()
+	m.Put(testKeyInt(67), "67")
+	m.Put(testKeyInt(68), "68")
+	if nil != m.Remove(nil) || nil != m.Remove(testKeyInt(69)) || 2 != m.Size() {
+		t.Fatal()
+	}
+	if "68" != m.Remove(testKeyInt(68)).(string) || 1 != m.Size() || false != m.Contains(testKeyInt(68)) {
+		t.Fatal()
+	}
+	if "67" != m.Remove(testKeyInt(67)).(string) || 0 != m.Size() || false != m.Contains(testKeyInt(67)) {
+		t.Fatal()
+	}
+}
+
+func TestConcurrentMap_Keys(t *testing.T) {
+	m := genTestStructureConcurrentMap()
+	keys := m.Keys()
+	if 10 != len(keys) {
+		t.Fatalf("unexpected: %v", keys)
+	}
+	list := make([]int, 0)
+	for _, k := range keys {
+		if nil == k {
+			continue
+		}
+		list = append(list, k.(testKeyStruct).val)
+	}
+	sort.Ints(list)
+	if 9 != len(list) || 11 != list[0] || 23 != list[5] || 33 != list[8] {
+		t.Fatalf("unexpected: %v", list)
+	}
+}
+
+func TestConcurrentMap_Values(t *testing.T) {
+	m := genTestStructureConcurrentMap()
+	list := make([]int, 0)
+	for _, v := range m.Values() {
+		list = append(list, v.(int))
+	}
+	sort.Ints(list)
+	if 10 != len(list) || 0 != list[0] || 11 != list[1] || 23 != list[6] || 33 != list[9] {
+		t.Fatalf("unexpected: %v", list)
+	}
+}
+
+func TestConcurrentMap_Pairs(t *testing.T) {
+	m := genTestStructureConcurrentMap()
+	pairs := m.Pairs()
+	if 10 != len(pairs) {
+		t.Fatalf("unexpected: %v", pairs)
+	}
+	for _, p := range pairs {
+		if nil == p {
+			t.Fatal("nil pair")
+		}
+		if nil == p.Key() {
+			if 0 != p.Value().(int) {
+				t.Fatal()
+			}
+			continue
+		}
+		if p.Key().(testKeyStruct).val != p.Value().(int) {
+			t.Fatal()
+		}
+	}
+}
+
+func TestConcurrentMap_Serialize(t *testing.T) {
+	m := genTestStructureConcurrentMap()
+	s := m.Serialize()
+	if 10 != len(s) || 0 != s["<nil>"].(int) || 11 != s["11"].(int) || 22 != s["22"].(int) || 33 != s["33"].(int) {
+		t.Fatalf("unexpected: %v", s)
+	}
+}
+
+func TestConcurrentMap_Iterator(t *testing.T) {
+	m := genTestStructureConcurrentMap()
+	it := m.Iterator()
+	// modifications after the iterator was created must not affect it
+	m.Put(testKeyInt(99), 99)
+	m.Remove(nil)
+	values := make([]int, 0)
+	for true == it.Next() {
+		values = append(values, it.Value().(int))
+	}
+	sort.Ints(values)
+	if 10 != len(values) || 0 != values[0] || 33 != values[9] {
+		t.Fatalf("unexpected: %v", values)
+	}
+	count := 0
+	for true == it.Previous() {
+		count++
+	}
+	if 10 != count {
+		t.Fatal(count)
+	}
+}
+
+func TestConcurrentMap_race(t *testing.T) {
+	m := NewConcurrentMap()
+	wg := sync.WaitGroup{}
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for x := 0; x < 500; x++ {
+				k := testKeyInt(w*1000 + x)
+				m.Put(k, x)
+				if x != m.Get(k).(int) || true != m.Contains(k) {
+					t.Error("unexpected value")
+					return
+				}
+				if 0 == x%2 {
+					m.Remove(k)
+				}
+				if 0 == x%100 {
+					m.Size()
+					m.Keys()
+					m.Serialize()
+					for it := m.Iterator(); true == it.Next(); {
+					}
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+	if 8*250 != m.Size() {
+		t.Fatal(m.Size())
+	}
+}
+
+func TestConcurrentMap_compute(t *testing.T) {
+	m := NewConcurrentMap().(ComputeMap)
+	wg := sync.WaitGroup{}
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for x := 0; x < 200; x++ {
+				k := testKeyInt(x % 10)
+				m.Merge(k, 1, func(old, new Value) Value {
+					return old.(int) + new.(int)
+				})
+				m.Compute(k, func(key Key, value Value) Value {
+					return value.(int) + 1
+				})
+				m.ComputeIfPresent(k, func(key Key, value Value) Value {
+					return value.(int) - 2
+				})
+				m.ComputeIfAbsent(testKeyInt(100+x%10), func(key Key) Value {
+					return x
+				})
+			}
+		}()
+	}
+	wg.Wait()
+	if 20 != m.Size() {
+		t.Fatal(m.Size())
+	}
+	for x := 0; x < 10; x++ {
+		if 0 != m.Get(testKeyInt(x)).(int) {
+			t.Fatal(m.Get(testKeyInt(x)))
+		}
+	}
+}
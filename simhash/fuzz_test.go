@@ -0,0 +1,271 @@
+//go:build go1.18
+// +build go1.18
+
+/*
+   Copyright 2017 Joseph Cumines
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+
+ */
+
+package simhash
+
+import (
+	"testing"
+)
+
+// fuzzKey is the key decoded from fuzz inputs, which collides heavily, so buckets will be large enough to be
+// treeified, and will be emptied often.
+type fuzzKey uint8
+
+func (k fuzzKey) Hash() int {
+	return int(k % 5)
+}
+
+func (k fuzzKey) Equals(other interface{}) bool {
+	o, ok := other.(fuzzKey)
+	return ok && k == o
+}
+
+func (k fuzzKey) Compare(other interface{}) int {
+	return int(k) - int(other.(fuzzKey))
+}
+
+// decodeFuzzKey maps a byte to a key, which may be nil.
+func decodeFuzzKey(b byte) Key {
+	if 0xff == b {
+		return nil
+	}
+	return fuzzKey(b % 48)
+}
+
+// fuzzMap decodes data as pairs of bytes, an operation and a key, applying each operation to m, and a reference
+// model, and failing if they ever disagree. Operations that step the iterator check that it only returns pairs that
+// are currently in the map, and, if distinct is true, that it never returns the same key twice while iterating
+// forwards, unless the key was removed in the meantime. If weak is false, the iterator must instead panic if any key
+// was added or removed since it was created, other than by the iterator itself.
+func fuzzMap(t *testing.T, m Map, distinct, weak bool, data []byte) {
+	model := make(map[Key]Value)
+	var (
+		it       Iterator
+		forwards bool
+		seen     map[Key]bool
+		modified bool
+	)
+	verify := func(i int) {
+		if size := m.Size(); len(model) != size {
+			t.Fatalf("%d: expected size %d, actual %d", i, len(model), size)
+		}
+		keys := m.Keys()
+		if len(model) != len(keys) {
+			t.Fatalf("%d: expected %d keys, actual %v", i, len(model), keys)
+		}
+		for _, k := range keys {
+			if _, ok := model[k]; false == ok {
+				t.Fatalf("%d: unexpected key %v", i, k)
+			}
+		}
+		pairs := make(map[Key]Value)
+		for it := m.Iterator(); true == it.Next(); {
+			if _, ok := pairs[it.Key()]; true == ok {
+				t.Fatalf("%d: iterator returned %v twice", i, it.Key())
+			}
+			pairs[it.Key()] = it.Value()
+		}
+		if len(model) != len(pairs) {
+			t.Fatalf("%d: expected iterator to return %d pairs, actual %d", i, len(model), len(pairs))
+		}
+		for k, v := range model {
+			if actual, ok := pairs[k]; false == ok || v != actual {
+				t.Fatalf("%d: expected iterator to return %v for %v, actual %v", i, v, k, actual)
+			}
+		}
+	}
+	step := func(i int, next bool) {
+		if nil == it {
+			return
+		}
+		if false == weak && true == modified {
+			defer func() {
+				if _, ok := recover().(*ConcurrentModificationError); false == ok {
+					t.Fatalf("%d: expected the iterator to panic", i)
+				}
+				it = nil
+			}()
+		}
+		var ok bool
+		if true == next {
+			ok = it.Next()
+		} else {
+			ok = it.Previous()
+		}
+		if next != forwards {
+			// the first step after changing direction doesn't move
+			forwards = next
+			seen = make(map[Key]bool)
+		}
+		if false == ok {
+			return
+		}
+		k := it.Key()
+		if v, ok := model[k]; false == ok || v != it.Value() {
+			t.Fatalf("%d: iterator returned %v=%v, which is not in the map", i, k, it.Value())
+		}
+		if true == distinct && true == forwards && true == seen[k] {
+			t.Fatalf("%d: iterator returned %v twice", i, k)
+		}
+		seen[k] = true
+	}
+	mutate := func(i int, remove bool) {
+		if nil == it {
+			return
+		}
+		k, current := it.Key(), nil != it.Pair()
+		_, exists := model[k]
+		defer func() {
+			r := recover()
+			switch {
+			case false == weak && true == modified:
+				if _, ok := r.(*ConcurrentModificationError); false == ok {
+					t.Fatalf("%d: expected the iterator to panic, actual %v", i, r)
+				}
+				it = nil
+			case false == current || false == exists:
+				if errNoCurrentPair != r {
+					t.Fatalf("%d: expected the iterator to have no current pair, actual %v", i, r)
+				}
+			case nil != r:
+				panic(r)
+			}
+		}()
+		if true == remove {
+			if actual := it.(MutableIterator).Remove(); model[k] != actual {
+				t.Fatalf("%d: Remove(): expected %v, actual %v", i, model[k], actual)
+			}
+			delete(model, k)
+			delete(seen, k)
+			if nil != it.Pair() {
+				t.Fatalf("%d: expected no current pair after Remove()", i)
+			}
+		} else {
+			if actual := it.(MutableIterator).SetValue(i); model[k] != actual {
+				t.Fatalf("%d: SetValue(): expected %v, actual %v", i, model[k], actual)
+			}
+			model[k] = i
+			if i != it.Value() {
+				t.Fatalf("%d: expected the current value to be replaced", i)
+			}
+		}
+	}
+	for i := 0; i+1 < len(data); i += 2 {
+		k := decodeFuzzKey(data[i+1])
+		switch data[i] % 10 {
+		case 0, 1:
+			// some values are nil, which must be distinct from missing keys
+			var v Value
+			if 0 != data[i]%20 {
+				v = i
+			}
+			expected, ok := model[k]
+			model[k] = v
+			modified = modified || false == ok
+			if actual := m.Put(k, v); expected != actual {
+				t.Fatalf("%d: Put(%v): expected %v, actual %v", i, k, expected, actual)
+			}
+		case 2:
+			expected, ok := model[k]
+			delete(model, k)
+			modified = modified || true == ok
+			delete(seen, k)
+			if actual := m.Remove(k); expected != actual {
+				t.Fatalf("%d: Remove(%v): expected %v, actual %v", i, k, expected, actual)
+			}
+		case 3:
+			expected, ok := model[k]
+			if actual := m.Get(k); expected != actual || ok != m.Contains(k) {
+				t.Fatalf("%d: Get(%v): expected %v, actual %v", i, k, expected, actual)
+			}
+		case 4:
+			step(i, true)
+		case 5:
+			step(i, false)
+		case 6:
+			it, forwards, seen, modified = m.Iterator(), true, make(map[Key]bool), false
+		case 7:
+			verify(i)
+		case 8:
+			mutate(i, true)
+		default:
+			mutate(i, false)
+		}
+		if size := m.Size(); len(model) != size {
+			t.Fatalf("%d: expected size %d, actual %d", i, len(model), size)
+		}
+	}
+	verify(len(data))
+}
+
+// addFuzzSeeds adds inputs which fill and empty buckets, and remove keys while iterating.
+func addFuzzSeeds(f *testing.F) {
+	f.Add([]byte{})
+	f.Add([]byte{0, 1, 0, 6, 0, 0xff, 7, 0, 2, 6, 2, 0xff, 3, 1, 3, 0xff, 7, 0})
+	fill := make([]byte, 0, 256)
+	for k := byte(0); k < 48; k++ {
+		fill = append(fill, 0, k)
+	}
+	f.Add(append(append([]byte{}, fill...), 6, 0, 4, 0, 2, 0, 4, 0, 2, 5, 4, 0, 4, 0, 5, 0, 5, 0, 7, 0))
+	drain := append([]byte{}, fill...)
+	for k := byte(0); k < 48; k++ {
+		drain = append(drain, 2, k, 3, k)
+	}
+	f.Add(append(drain, 7, 0))
+	// remove every other key using the iterator, forwards then backwards, and replace the rest
+	iterate := append(append([]byte{}, fill...), 6, 0)
+	for k := 0; k < 48; k++ {
+		iterate = append(iterate, 4, 0, byte(8+k%2), 0)
+	}
+	iterate = append(iterate, 7, 0, 6, 0)
+	for k := 0; k < 24; k++ {
+		iterate = append(iterate, 5, 0, byte(8+k%2), 0)
+	}
+	f.Add(append(iterate, 7, 0))
+}
+
+func FuzzHashMap(f *testing.F) {
+	addFuzzSeeds(f)
+	f.Fuzz(func(t *testing.T, data []byte) {
+		fuzzMap(t, NewMap(WithoutHashSeed()), true, false, data)
+	})
+}
+
+func FuzzHashMap_seeded(f *testing.F) {
+	addFuzzSeeds(f)
+	f.Fuzz(func(t *testing.T, data []byte) {
+		fuzzMap(t, NewMap(WithHashSeed(0x5eed), WithWeaklyConsistentIterators()), true, true, data)
+	})
+}
+
+func FuzzSwissMap(f *testing.F) {
+	addFuzzSeeds(f)
+	f.Fuzz(func(t *testing.T, data []byte) {
+		fuzzMap(t, NewMap(WithOpenAddressing(), WithoutHashSeed()), true, false, data)
+	})
+}
+
+func FuzzSwissMap_weak(f *testing.F) {
+	addFuzzSeeds(f)
+	f.Fuzz(func(t *testing.T, data []byte) {
+		// the iterator will repeat pairs if the map grows during iteration
+		fuzzMap(t, NewMap(WithOpenAddressing(), WithoutHashSeed(), WithWeaklyConsistentIterators()), false, true, data)
+	})
+}
@@ -0,0 +1,131 @@
+//go:build go1.18
+// +build go1.18
+
+/*
+   Copyright 2017 Joseph Cumines
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+
+ */
+
+package generic
+
+// Iterator is the type-parameterised equivalent of simhash.Iterator, and follows the same rules for changing
+// direction. Before the first call to Next or Previous, Pair will return nil, and Key and Value the zero values.
+type Iterator[K any, V any] interface {
+	// Pair will return the current pair in the iteration, initially nil.
+	Pair() Pair[K, V]
+
+	// Value will return the current value in the iteration, initially the zero value.
+	Value() V
+
+	// Key will return the current key in the iteration, initially the zero value.
+	Key() K
+
+	// Next moves the iterator in the forwards direction, or reverses the iterator (and doesn't move) if it is
+	// currently iterating backwards, and will return false if there were no more items left to iterate.
+	Next() bool
+
+	// Previous moves the iterator in the backwards direction, or reverses the iterator (and doesn't move) if it is
+	// currently iterating forwards, and will return false if there were no more items left to iterate.
+	Previous() bool
+}
+
+type iterator[K Key[K], V any] struct {
+	m        *hashMap[K, V]
+	hList    []int
+	pair     pair[K, V]
+	h        int
+	i        int
+	forwards bool
+	active   bool
+	valid    bool
+}
+
+func (it *iterator[K, V]) Pair() Pair[K, V] {
+	if false == it.valid {
+		return nil
+	}
+	return it.pair
+}
+
+func (it *iterator[K, V]) Value() V {
+	return it.pair.value
+}
+
+func (it *iterator[K, V]) Key() K {
+	return it.pair.key
+}
+
+func (it *iterator[K, V]) increment(forwards bool) bool {
+	if 0 == len(it.hList) {
+		return false
+	}
+	// Find if an index is outside the bounds of a slice.
+	done := func(index, size int) bool {
+		return 0 >= size || index >= size || index < 0
+	}
+	inc := 1
+	if false == forwards {
+		inc = -1
+	}
+	// Resets it.i to either the start or the end of the relevant box, depending on the direction of the increment.
+	resetI := func() {
+		it.i = 0
+		if inc < 0 && false == done(it.h, len(it.hList)) {
+			it.i = len(it.m.m[it.hList[it.h]]) - 1
+		}
+	}
+	resetH := func() {
+		it.h = 0
+		if inc < 0 {
+			it.h = len(it.hList) - 1
+		}
+		resetI()
+	}
+	if false == it.active {
+		it.active = true
+		it.forwards = forwards
+		resetH()
+	}
+	// if we changed directions, we need to increment until back in the correct range (if we are not in range).
+	if forwards != it.forwards {
+		if true == done(it.h, len(it.hList)) {
+			resetH()
+		} else {
+			it.i += inc
+		}
+	}
+	it.forwards = forwards
+	for false == done(it.h, len(it.hList)) {
+		hBox := it.m.m[it.hList[it.h]]
+		if true == done(it.i, len(hBox)) {
+			it.h += inc
+			resetI()
+			continue
+		}
+		it.pair = hBox[it.i]
+		it.valid = true
+		it.i += inc
+		return true
+	}
+	return false
+}
+
+func (it *iterator[K, V]) Next() bool {
+	return it.increment(true)
+}
+
+func (it *iterator[K, V]) Previous() bool {
+	return it.increment(false)
+}
@@ -0,0 +1,77 @@
+//go:build go1.18
+// +build go1.18
+
+/*
+   Copyright 2017 Joseph Cumines
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+
+ */
+
+package generic
+
+import (
+	"sort"
+	"testing"
+)
+
+func TestIterator(t *testing.T) {
+	m := genTestStructureHashMap()
+	it := m.Iterator()
+	if nil != it.Pair() || 0 != it.Value() || (testKeyStruct{}) != it.Key() {
+		t.Fatal()
+	}
+	collect := func(step func() bool) []int {
+		values := make([]int, 0)
+		for true == step() {
+			if it.Value() != it.Key().val || it.Value() != it.Pair().Value() {
+				t.Fatal()
+			}
+			values = append(values, it.Value())
+		}
+		return values
+	}
+	forwards := collect(it.Next)
+	backwards := collect(it.Previous)
+	if 9 != len(forwards) || 9 != len(backwards) {
+		t.Fatal(forwards, backwards)
+	}
+	for i := range forwards {
+		if forwards[i] != backwards[len(backwards)-1-i] {
+			t.Fatal(forwards, backwards)
+		}
+	}
+	sort.Ints(forwards)
+	if 11 != forwards[0] || 33 != forwards[8] {
+		t.Fatal(forwards)
+	}
+
+	// changing direction will not move
+	it = m.Iterator()
+	it.Next()
+	it.Next()
+	second := it.Value()
+	if true != it.Previous() || second != it.Value() {
+		t.Fatal()
+	}
+	if true != it.Previous() || second == it.Value() {
+		t.Fatal()
+	}
+	if false != it.Previous() {
+		t.Fatal()
+	}
+
+	if true == NewMap[testKeyInt, int]().Iterator().Next() {
+		t.Fatal()
+	}
+}
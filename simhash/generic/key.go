@@ -0,0 +1,39 @@
+//go:build go1.18
+// +build go1.18
+
+/*
+   Copyright 2017 Joseph Cumines
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+
+ */
+
+// Package generic provides a type-parameterised variant of the simhash package, where keys and values are
+// statically typed, removing the need for type assertions at each call site. It shares the same bucketing algorithm
+// as simhash, and therefore the same performance characteristics.
+package generic
+
+// Key is the type-parameterised equivalent of simhash.Key, where K will usually be the implementing type itself,
+// e.g. `type myKey int` would implement Key[myKey]. Unlike simhash, only interface-typed keys that are nil receive
+// special handling (they have a hash of 0, and equal only other nil keys), other key values must be safe to call
+// Hash and Equals on.
+type Key[K any] interface {
+	Hash() int
+	Equals(other K) bool
+}
+
+// Key64 may optionally be implemented by a Key, the equivalent of simhash.Key64, in which case Hash64 will be
+// preferred over Hash.
+type Key64 interface {
+	Hash64() uint64
+}
@@ -0,0 +1,81 @@
+//go:build go1.18
+// +build go1.18
+
+/*
+   Copyright 2017 Joseph Cumines
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+
+ */
+
+package generic
+
+import "strconv"
+
+type testKeyInt int
+
+func (k testKeyInt) Hash() int {
+	return int(k)
+}
+
+func (k testKeyInt) Equals(other testKeyInt) bool {
+	return k == other
+}
+
+type testKeyStruct struct {
+	hash int
+	val  int
+}
+
+func (k testKeyStruct) Hash() int {
+	return k.hash
+}
+
+func (k testKeyStruct) Equals(other testKeyStruct) bool {
+	return k == other
+}
+
+func (k testKeyStruct) String() string {
+	return strconv.Itoa(k.val)
+}
+
+// testKeyIface allows testing nil keys
+type testKeyIface interface {
+	Key[testKeyIface]
+}
+
+type testKeyIfaceImpl int
+
+func (k testKeyIfaceImpl) Hash() int {
+	return int(k)
+}
+
+func (k testKeyIfaceImpl) Equals(other testKeyIface) bool {
+	o, ok := other.(testKeyIfaceImpl)
+	return ok && o == k
+}
+
+// testKey64 has a 64 bit hash, where the upper bits are the value, and the lower bits are always 0.
+type testKey64 int
+
+func (k testKey64) Hash() int {
+	return 0
+}
+
+func (k testKey64) Hash64() uint64 {
+	return uint64(k) << 32
+}
+
+func (k testKey64) Equals(other testKey64) bool {
+	return k == other
+}
@@ -0,0 +1,241 @@
+//go:build go1.18
+// +build go1.18
+
+/*
+   Copyright 2017 Joseph Cumines
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+
+ */
+
+package generic
+
+import (
+	"fmt"
+
+	"github.com/joeycumines/go-hashmap/simhash/internal/hashbits"
+)
+
+// Map provides the specification for a statically typed hashmap, the equivalent of simhash.Map. Operations that
+// would return a nil value in simhash instead return the zero value, along with a flag indicating if the key existed.
+type Map[K Key[K], V any] interface {
+	// Contains will return true if the key exists in the map.
+	Contains(key K) bool
+
+	// Get will return the value and true if it exists in the map, or the zero value and false if it doesn't.
+	Get(key K) (V, bool)
+
+	// Put will store value as key in the map, and will return any existing value, and true if it existed.
+	Put(key K, value V) (V, bool)
+
+	// Remove removes any value that existed for key in the map, and will return it, and true if it existed.
+	Remove(key K) (V, bool)
+
+	// Keys returns a slice containing all the keys in the map.
+	Keys() []K
+
+	// Values returns a slice containing all the values in the map.
+	Values() []V
+
+	// Pairs returns a slice containing all the key-value pairs in the map.
+	Pairs() []Pair[K, V]
+
+	// Size returns the number of key-value pairs in the map.
+	Size() int
+
+	// Serialize behaves the same as simhash.Map.Serialize, using fmt.Stringer, if the keys implement it.
+	Serialize() map[string]V
+
+	// Get a new Iterator for this map, which should be stable.
+	Iterator() Iterator[K, V]
+}
+
+// hashMap is the type-parameterised equivalent of the simhash hashMap, storing pairs by value to avoid allocating
+// on each Put. It has the same concurrency restrictions.
+type hashMap[K Key[K], V any] struct {
+	m    map[int][]pair[K, V]
+	size int
+	// iface is true if K is an interface type, in which case keys may be nil, and may or may not implement Key64
+	iface bool
+	// key64 is true if K is not an interface type, and implements Key64
+	key64 bool
+	seed  uint64
+}
+
+func isNil[K any](key K) bool {
+	return nil == any(key)
+}
+
+// hashOf returns the hash of key, only converting it to an interface if K is an interface type (which is free), or
+// implements Key64, as converting other keys would allocate on every operation.
+func (m *hashMap[K, V]) hashOf(key K) int {
+	if true == m.iface {
+		if true == isNil(key) {
+			return 0
+		}
+		if k, ok := any(key).(Key64); true == ok {
+			return hashbits.Fold(k.Hash64())
+		}
+	} else if true == m.key64 {
+		return hashbits.Fold(any(key).(Key64).Hash64())
+	}
+	return key.Hash()
+}
+
+// hash returns the bucket of key, which is it's hash, mixed with the random seed of the map, the same as simhash.
+func (m *hashMap[K, V]) hash(key K) int {
+	return int(hashbits.Mix(uint64(m.hashOf(key)) ^ m.seed))
+}
+
+func (m *hashMap[K, V]) equals(a, b K) bool {
+	if false == m.iface {
+		return a.Equals(b)
+	}
+	aNil, bNil := isNil(a), isNil(b)
+	return (true == aNil && true == bNil) || (false == aNil && false == bNil && a.Equals(b))
+}
+
+func (m *hashMap[K, V]) lookup(key K) (int, int, bool) {
+	h := m.hash(key)
+	for i, p := range m.m[h] {
+		if true == m.equals(key, p.key) {
+			return h, i, true
+		}
+	}
+	return 0, 0, false
+}
+
+func (m *hashMap[K, V]) Contains(key K) bool {
+	_, _, ok := m.lookup(key)
+	return ok
+}
+
+func (m *hashMap[K, V]) Get(key K) (V, bool) {
+	h, i, ok := m.lookup(key)
+	if false == ok {
+		var zero V
+		return zero, false
+	}
+	return m.m[h][i].value, true
+}
+
+func (m *hashMap[K, V]) Put(key K, value V) (V, bool) {
+	if h, i, ok := m.lookup(key); true == ok {
+		v := m.m[h][i].value
+		m.m[h][i] = pair[K, V]{key, value}
+		return v, true
+	}
+	h := m.hash(key)
+	if nil == m.m[h] {
+		m.m[h] = make([]pair[K, V], 0, 1)
+	}
+	m.m[h] = append(m.m[h], pair[K, V]{key, value})
+	m.size++
+	var zero V
+	return zero, false
+}
+
+func (m *hashMap[K, V]) Remove(key K) (V, bool) {
+	h, i, ok := m.lookup(key)
+	if false == ok {
+		var zero V
+		return zero, false
+	}
+	pairs := m.m[h]
+	v := pairs[i].value
+	pairs[i] = pairs[len(pairs)-1]
+	pairs[len(pairs)-1] = pair[K, V]{}
+	m.m[h] = pairs[:len(pairs)-1]
+	if 0 == len(m.m[h]) {
+		delete(m.m, h)
+	}
+	m.size--
+	return v, true
+}
+
+func (m *hashMap[K, V]) Keys() []K {
+	keys := make([]K, 0, m.size)
+	for _, pairs := range m.m {
+		for _, p := range pairs {
+			keys = append(keys, p.key)
+		}
+	}
+	return keys
+}
+
+func (m *hashMap[K, V]) Values() []V {
+	values := make([]V, 0, m.size)
+	for _, pairs := range m.m {
+		for _, p := range pairs {
+			values = append(values, p.value)
+		}
+	}
+	return values
+}
+
+func (m *hashMap[K, V]) Pairs() []Pair[K, V] {
+	pairList := make([]Pair[K, V], 0, m.size)
+	for _, pairs := range m.m {
+		for _, p := range pairs {
+			pairList = append(pairList, p)
+		}
+	}
+	return pairList
+}
+
+func (m *hashMap[K, V]) Size() int {
+	return m.size
+}
+
+func (m *hashMap[K, V]) Serialize() map[string]V {
+	serialized := make(map[string]V)
+	for _, pairs := range m.m {
+		for _, p := range pairs {
+			var k string
+			if kStringer, ok := any(p.key).(fmt.Stringer); true == ok && false == isNil(p.key) {
+				k = kStringer.String()
+			} else {
+				k = fmt.Sprintf("%v", p.key)
+			}
+			serialized[k] = p.value
+		}
+	}
+	return serialized
+}
+
+func (m *hashMap[K, V]) Iterator() Iterator[K, V] {
+	// enumerate the map keys ahead of time, they are only integers anyway
+	hList := make([]int, 0, len(m.m))
+	for h := range m.m {
+		hList = append(hList, h)
+	}
+	return &iterator[K, V]{
+		m:        m,
+		hList:    hList,
+		forwards: true,
+	}
+}
+
+// NewMap creates a new, empty, Map. It is not safe for concurrent use. Keys are placed using a random hash seed, the
+// same as the default for simhash.NewMap, see simhash.WithRandomHashSeed.
+func NewMap[K Key[K], V any]() Map[K, V] {
+	m := &hashMap[K, V]{m: make(map[int][]pair[K, V]), seed: hashbits.RandomSeed()}
+	// the zero value of K is only nil if K is an interface type, otherwise whether it implements Key64 is static
+	var zero K
+	if true == isNil(zero) {
+		m.iface = true
+	} else {
+		_, m.key64 = any(zero).(Key64)
+	}
+	return m
+}
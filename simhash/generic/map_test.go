@@ -0,0 +1,180 @@
+//go:build go1.18
+// +build go1.18
+
+/*
+   Copyright 2017 Joseph Cumines
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+
+ */
+
+package generic
+
+import (
+	"sort"
+	"testing"
+)
+
+func genTestStructureHashMap() *hashMap[testKeyStruct, int] {
+	m := NewMap[testKeyStruct, int]().(*hashMap[testKeyStruct, int])
+	for x := 1; x <= 3; x++ {
+		for y := 1; y <= 3; y++ {
+			i := x*10 + y
+			m.Put(testKeyStruct{x, i}, i)
+		}
+	}
+	return m
+}
+
+func TestNewMap(t *testing.T) {
+	m := NewMap[testKeyInt, string]().(*hashMap[testKeyInt, string])
+	if nil == m.m || 0 != len(m.m) || 0 != m.size {
+		t.Fatal()
+	}
+	// each map has a random seed
+	if m.hash(1) == NewMap[testKeyInt, string]().(*hashMap[testKeyInt, string]).hash(1) || m.hash(1) != m.hash(1) {
+		t.Fatal()
+	}
+}
+
+func TestHashMap_Put(t *testing.T) {
+	m := NewMap[testKeyInt, string]()
+	if v, ok := m.Put(67, "67"); "" != v || false != ok || 1 != m.Size() {
+		t.Fatal()
+	}
+	if v, ok := m.Put(67, "sixty seven"); "67" != v || true != ok || 1 != m.Size() {
+		t.Fatal()
+	}
+	if v, ok := m.Get(67); "sixty seven" != v || true != ok {
+		t.Fatal()
+	}
+	if v, ok := m.Get(68); "" != v || false != ok || false != m.Contains(68) || true != m.Contains(67) {
+		t.Fatal()
+	}
+}
+
+func TestHashMap_Remove(t *testing.T) {
+	m := genTestStructureHashMap()
+	if v, ok := m.Remove(testKeyStruct{1, 99}); 0 != v || false != ok || 9 != m.Size() {
+		t.Fatal()
+	}
+	if v, ok := m.Remove(testKeyStruct{1, 11}); 11 != v || true != ok || 8 != m.Size() || 2 != len(m.m[m.hash(testKeyStruct{1, 11})]) {
+		t.Fatal()
+	}
+	m.Remove(testKeyStruct{1, 12})
+	m.Remove(testKeyStruct{1, 13})
+	if 6 != m.Size() || 2 != len(m.m) || true == m.Contains(testKeyStruct{1, 12}) || false == m.Contains(testKeyStruct{2, 21}) {
+		t.Fatal()
+	}
+}
+
+func TestHashMap_nilKey(t *testing.T) {
+	m := NewMap[testKeyIface, int]()
+	if _, ok := m.Put(nil, 1); true == ok || 1 != m.Size() {
+		t.Fatal()
+	}
+	m.Put(testKeyIfaceImpl(0), 2)
+	if v, ok := m.Get(nil); 1 != v || true != ok || 2 != m.Size() {
+		t.Fatal()
+	}
+	if v, ok := m.Get(testKeyIfaceImpl(0)); 2 != v || true != ok {
+		t.Fatal()
+	}
+	if v, ok := m.Remove(nil); 1 != v || true != ok || 1 != m.Size() || true == m.Contains(nil) {
+		t.Fatal()
+	}
+	if s := m.Serialize(); 1 != len(s) || 2 != s["0"] {
+		t.Fatalf("unexpected: %v", s)
+	}
+}
+
+func TestHashMap_Key64(t *testing.T) {
+	m := NewMap[testKey64, int]().(*hashMap[testKey64, int])
+	for i := 0; i < 100; i++ {
+		m.Put(testKey64(i), i)
+	}
+	// every key has the same Hash, but a distinct Hash64
+	if 100 != m.Size() || 100 != len(m.m) {
+		t.Fatal(m.Size(), len(m.m))
+	}
+	for i := 0; i < 100; i++ {
+		if v, ok := m.Get(testKey64(i)); i != v || true != ok {
+			t.Fatal(i)
+		}
+	}
+}
+
+func TestHashMap_allocs(t *testing.T) {
+	m := NewMap[testKeyStruct, int]()
+	m.Put(testKeyStruct{1, 1000}, 1)
+	k, missing := testKeyStruct{1, 1000}, testKeyStruct{1, 1001}
+	if n := testing.AllocsPerRun(100, func() {
+		m.Get(k)
+		m.Contains(missing)
+		m.Put(k, 2)
+		m.Remove(missing)
+	}); 0 != n {
+		t.Fatal(n)
+	}
+	mi := NewMap[testKeyIface, int]()
+	var ki testKeyIface = testKeyIfaceImpl(1000)
+	mi.Put(ki, 1)
+	if n := testing.AllocsPerRun(100, func() {
+		mi.Get(ki)
+		mi.Get(nil)
+	}); 0 != n {
+		t.Fatal(n)
+	}
+}
+
+func TestHashMap_Keys(t *testing.T) {
+	m := genTestStructureHashMap()
+	list := make([]int, 0)
+	for _, k := range m.Keys() {
+		list = append(list, k.val)
+	}
+	sort.Ints(list)
+	if 9 != len(list) || 11 != list[0] || 22 != list[4] || 33 != list[8] {
+		t.Fatalf("unexpected: %v", list)
+	}
+}
+
+func TestHashMap_Values(t *testing.T) {
+	m := genTestStructureHashMap()
+	list := m.Values()
+	sort.Ints(list)
+	if 9 != len(list) || 11 != list[0] || 22 != list[4] || 33 != list[8] {
+		t.Fatalf("unexpected: %v", list)
+	}
+}
+
+func TestHashMap_Pairs(t *testing.T) {
+	m := genTestStructureHashMap()
+	pairs := m.Pairs()
+	if 9 != len(pairs) {
+		t.Fatalf("unexpected: %v", pairs)
+	}
+	for _, p := range pairs {
+		if p.Key().val != p.Value() {
+			t.Fatalf("unexpected: %v", p)
+		}
+	}
+}
+
+func TestHashMap_Serialize(t *testing.T) {
+	m := genTestStructureHashMap()
+	s := m.Serialize()
+	if 9 != len(s) || 11 != s["11"] || 22 != s["22"] || 33 != s["33"] {
+		t.Fatalf("unexpected: %v", s)
+	}
+}
@@ -0,0 +1,45 @@
+//go:build go1.18
+// +build go1.18
+
+/*
+   Copyright 2017 Joseph Cumines
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+
+ */
+
+package generic
+
+// Pair represents a key-value relationship.
+type Pair[K any, V any] interface {
+	Key() K
+	Value() V
+}
+
+type pair[K any, V any] struct {
+	key   K
+	value V
+}
+
+func (p pair[K, V]) Key() K {
+	return p.key
+}
+
+func (p pair[K, V]) Value() V {
+	return p.value
+}
+
+// NewPair creates a key-value pair.
+func NewPair[K any, V any](key K, value V) Pair[K, V] {
+	return pair[K, V]{key, value}
+}
@@ -0,0 +1,36 @@
+//go:build go1.18
+// +build go1.18
+
+/*
+   Copyright 2017 Joseph Cumines
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+
+ */
+
+package generic
+
+import "testing"
+
+func TestPair(t *testing.T) {
+	p := NewPair(testKeyInt(4), "test")
+	if nil == p {
+		t.Fatalf("unexpected: %v", p)
+	}
+	if 4 != p.Key().Hash() {
+		t.Fatalf("unexpected: %v", p)
+	}
+	if "test" != p.Value() {
+		t.Fatalf("unexpected: %v", p)
+	}
+}
@@ -0,0 +1,162 @@
+/*
+   Copyright 2017 Joseph Cumines
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+
+ */
+
+package hashing
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+
+	"github.com/joeycumines/go-hashmap/simhash"
+	"github.com/joeycumines/go-hashmap/simhash/internal/hashbits"
+)
+
+// Hasher incrementally builds a hash from a sequence of values, where both the values and their order affect the
+// result, e.g. `hashing.NewHasher().String(k.name).Int(k.id).Hash()`. The zero value is not valid, see NewHasher.
+// The results are stable across runs, and platforms, apart from the size of the int returned by Hash.
+type Hasher struct {
+	h uint64
+}
+
+// NewHasher returns a new Hasher, with no values.
+func NewHasher() *Hasher {
+	return &Hasher{hashbits.Offset}
+}
+
+// add combines a 64 bit hash into the hasher.
+func (h *Hasher) add(v uint64) *Hasher {
+	h.h = Mix(h.h) ^ v
+	return h
+}
+
+// Uint64 adds v to the hash.
+func (h *Hasher) Uint64(v uint64) *Hasher {
+	return h.add(v)
+}
+
+// Int64 adds v to the hash, which is the same as Uint64(uint64(v)).
+func (h *Hasher) Int64(v int64) *Hasher {
+	return h.add(uint64(v))
+}
+
+// Int adds v to the hash, which is the same as Int64(int64(v)).
+func (h *Hasher) Int(v int) *Hasher {
+	return h.add(uint64(v))
+}
+
+// Bool adds v to the hash, which is the same as Int(1) if v is true, otherwise Int(0).
+func (h *Hasher) Bool(v bool) *Hasher {
+	if true == v {
+		return h.add(1)
+	}
+	return h.add(0)
+}
+
+// Float64 adds v to the hash, where every NaN value has the same hash, but 0 and -0 have different hashes, the same
+// as Java's Double.hashCode.
+func (h *Hasher) Float64(v float64) *Hasher {
+	if v != v {
+		return h.add(0x7ff8000000000000)
+	}
+	return h.add(math.Float64bits(v))
+}
+
+// String adds the contents of v to the hash.
+func (h *Hasher) String(v string) *Hasher {
+	return h.add(FNV64a(v))
+}
+
+// Bytes adds the contents of v to the hash, which is the same as String(string(v)).
+func (h *Hasher) Bytes(v []byte) *Hasher {
+	return h.add(FNV64aBytes(v))
+}
+
+// Key adds the hash of v to the hash, using Hash64 if it implements simhash.Key64, where a nil key, or a key that is
+// a nil pointer, has a hash distinct from 0.
+func (h *Hasher) Key(v simhash.Key) *Hasher {
+	if true == isNil(v) {
+		return h.add(hashbits.Nil)
+	}
+	return h.add(simhash.DeriveHash64(v))
+}
+
+// Value adds v to the hash, using the method for it's type, where v may be nil, any of the types supported by the
+// other methods, or a simhash.Key, or any other type, which will be hashed using it's fmt.Sprintf %#v representation,
+// which is only stable if it is fully determined by the value, e.g. it contains no pointers.
+// Integers are hashed by value, and not type, e.g. int(1) and uint8(1) will have the same hash, which is also true of
+// float32 and float64, and strings and byte slices.
+func (h *Hasher) Value(v interface{}) *Hasher {
+	switch v := v.(type) {
+	case nil:
+		return h.add(hashbits.Nil)
+	case simhash.Key:
+		return h.Key(v)
+	case string:
+		return h.String(v)
+	case []byte:
+		return h.Bytes(v)
+	case bool:
+		return h.Bool(v)
+	case int:
+		return h.Int(v)
+	case int8:
+		return h.Int(int(v))
+	case int16:
+		return h.Int(int(v))
+	case int32:
+		return h.Int(int(v))
+	case int64:
+		return h.Int64(v)
+	case uint:
+		return h.Uint64(uint64(v))
+	case uint8:
+		return h.Uint64(uint64(v))
+	case uint16:
+		return h.Uint64(uint64(v))
+	case uint32:
+		return h.Uint64(uint64(v))
+	case uint64:
+		return h.Uint64(v)
+	case uintptr:
+		return h.Uint64(uint64(v))
+	case float32:
+		return h.Float64(float64(v))
+	case float64:
+		return h.Float64(v)
+	}
+	return h.String(fmt.Sprintf("%#v", v))
+}
+
+// Hash64 returns the hash of the values added so far, which does not modify the hasher.
+func (h *Hasher) Hash64() uint64 {
+	return Mix(h.h)
+}
+
+// Hash returns Hash64, converted to an int, suitable for implementing simhash.Key.
+func (h *Hasher) Hash() int {
+	return Fold(h.Hash64())
+}
+
+// isNil returns true if v is nil, or a nil pointer, which cannot be safely called.
+func isNil(v interface{}) bool {
+	if nil == v {
+		return true
+	}
+	r := reflect.ValueOf(v)
+	return reflect.Ptr == r.Kind() && true == r.IsNil()
+}
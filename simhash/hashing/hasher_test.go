@@ -0,0 +1,171 @@
+/*
+   Copyright 2017 Joseph Cumines
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+
+ */
+
+package hashing
+
+import (
+	"math"
+	"strconv"
+	"testing"
+)
+
+type testKeyInt int
+
+func (k testKeyInt) Hash() int {
+	return int(k)
+}
+
+func (k testKeyInt) Equals(other interface{}) bool {
+	o, ok := other.(testKeyInt)
+	return ok && k == o
+}
+
+type testKeyPtr struct {
+	val int
+}
+
+func (k *testKeyPtr) Hash() int {
+	return k.val
+}
+
+func (k *testKeyPtr) Equals(other interface{}) bool {
+	o, ok := other.(*testKeyPtr)
+	return ok && nil != k && nil != o && k.val == o.val
+}
+
+type testKey64 int
+
+func (k testKey64) Hash() int {
+	return 0
+}
+
+func (k testKey64) Hash64() uint64 {
+	return uint64(k) << 32
+}
+
+func (k testKey64) Equals(other interface{}) bool {
+	o, ok := other.(testKey64)
+	return ok && k == o
+}
+
+// TestHasher_stable guards against accidental changes, as hashes may be persisted.
+func TestHasher_stable(t *testing.T) {
+	for i, tc := range []struct {
+		h        *Hasher
+		expected uint64
+	}{
+		{NewHasher(), 0x9ca066f1a4ab2eea},
+		{NewHasher().String("hello"), 0xa4d7d8681080be23},
+		{NewHasher().Int(1).Int(2).Int(3), 0x7d72c2954b043f52},
+		{NewHasher().Value(nil).Value("a").Value(1.5).Value(true), 0xaa5db7606e827bda},
+	} {
+		if actual := tc.h.Hash64(); tc.expected != actual {
+			t.Errorf("%d: expected %#x, actual %#x", i, tc.expected, actual)
+		}
+	}
+}
+
+func TestHasher_Hash(t *testing.T) {
+	h := NewHasher().String("a")
+	if h.Hash64() != h.Hash64() || h.Hash() != Fold(h.Hash64()) {
+		t.Fatal()
+	}
+	if 64 == strconv.IntSize && int(h.Hash64()) != h.Hash() {
+		t.Fatal()
+	}
+}
+
+func TestHasher_order(t *testing.T) {
+	if NewHasher().Int(1).Int(2).Hash64() == NewHasher().Int(2).Int(1).Hash64() {
+		t.Fatal()
+	}
+	if NewHasher().Int(0).Hash64() == NewHasher().Hash64() || NewHasher().Int(0).Int(0).Hash64() == NewHasher().Int(0).Hash64() {
+		t.Fatal()
+	}
+	if NewHasher().String("ab").String("c").Hash64() == NewHasher().String("a").String("bc").Hash64() {
+		t.Fatal()
+	}
+}
+
+func TestHasher_equivalences(t *testing.T) {
+	for i, tc := range []struct{ a, b *Hasher }{
+		{NewHasher().Int(-1), NewHasher().Int64(-1)},
+		{NewHasher().Int64(-1), NewHasher().Uint64(math.MaxUint64)},
+		{NewHasher().Bool(true), NewHasher().Int(1)},
+		{NewHasher().Bool(false), NewHasher().Int(0)},
+		{NewHasher().Bytes([]byte("abc")), NewHasher().String("abc")},
+		{NewHasher().Bytes(nil), NewHasher().String("")},
+		{NewHasher().Float64(math.NaN()), NewHasher().Float64(math.Float64frombits(0xfff0000000000001))},
+		{NewHasher().Key(testKeyInt(5)), NewHasher().Key(testKeyInt(5))},
+		{NewHasher().Key(nil), NewHasher().Value(nil)},
+		{NewHasher().Key((*testKeyPtr)(nil)), NewHasher().Value(nil)},
+		{NewHasher().Value((*testKeyPtr)(nil)), NewHasher().Value(nil)},
+		{NewHasher().Key(testKey64(3)), NewHasher().Uint64(3 << 32)},
+		{NewHasher().Value(int8(-3)), NewHasher().Int(-3)},
+		{NewHasher().Value(int16(-3)), NewHasher().Int(-3)},
+		{NewHasher().Value(int32(-3)), NewHasher().Int(-3)},
+		{NewHasher().Value(int64(-3)), NewHasher().Int(-3)},
+		{NewHasher().Value(uint(3)), NewHasher().Int(3)},
+		{NewHasher().Value(uint8(3)), NewHasher().Int(3)},
+		{NewHasher().Value(uint16(3)), NewHasher().Int(3)},
+		{NewHasher().Value(uint32(3)), NewHasher().Int(3)},
+		{NewHasher().Value(uint64(3)), NewHasher().Int(3)},
+		{NewHasher().Value(uintptr(3)), NewHasher().Int(3)},
+		{NewHasher().Value(float32(1.5)), NewHasher().Float64(1.5)},
+		{NewHasher().Value(1.5), NewHasher().Float64(1.5)},
+		{NewHasher().Value(true), NewHasher().Bool(true)},
+		{NewHasher().Value("a"), NewHasher().String("a")},
+		{NewHasher().Value([]byte("a")), NewHasher().String("a")},
+		{NewHasher().Value(testKeyInt(5)), NewHasher().Key(testKeyInt(5))},
+		{NewHasher().Value(struct{ A int }{1}), NewHasher().String("struct { A int }{A:1}")},
+	} {
+		if tc.a.Hash64() != tc.b.Hash64() {
+			t.Error(i)
+		}
+	}
+}
+
+func TestHasher_differences(t *testing.T) {
+	for i, tc := range []struct{ a, b *Hasher }{
+		{NewHasher().Float64(0), NewHasher().Float64(math.Copysign(0, -1))},
+		{NewHasher().Float64(1), NewHasher().Int(1)},
+		{NewHasher().Key(testKeyInt(0)), NewHasher().Key(nil)},
+		{NewHasher().Key(testKeyInt(5)), NewHasher().Key(testKeyInt(6))},
+		{NewHasher().Key(&testKeyPtr{0}), NewHasher().Key(nil)},
+		{NewHasher().Value(struct{ A int }{1}), NewHasher().Value(struct{ A int }{2})},
+	} {
+		if tc.a.Hash64() == tc.b.Hash64() {
+			t.Error(i)
+		}
+	}
+}
+
+func TestHasher_distribution(t *testing.T) {
+	// sequential inputs should spread evenly across buckets, using both the low and high bits
+	const buckets = 64
+	for _, shift := range []uint{0, 58} {
+		counts := make([]int, buckets)
+		for i := 0; i < buckets*100; i++ {
+			counts[(NewHasher().Int(i).Hash64()>>shift)%buckets]++
+		}
+		for b, count := range counts {
+			if count < 50 || count > 150 {
+				t.Fatal(shift, b, count)
+			}
+		}
+	}
+}
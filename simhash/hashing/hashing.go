@@ -0,0 +1,94 @@
+/*
+   Copyright 2017 Joseph Cumines
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+
+ */
+
+// Package hashing provides utilities for implementing simhash.Key, equivalent to Java's Objects.hash, which combine
+// the hashes of several values, with consistent nil handling, and a good distribution. Every function is built on
+// Hasher, and the results are stable across runs and platforms, apart from the size of the returned int, so they may
+// be persisted, or shared between processes.
+package hashing
+
+import (
+	"github.com/joeycumines/go-hashmap/simhash"
+	"github.com/joeycumines/go-hashmap/simhash/internal/hashbits"
+)
+
+// HashCombine combines several hashes into one, where the order of the hashes matters, e.g. to combine the hashes of
+// the fields of a struct, that are already keys.
+func HashCombine(hashes ...int) int {
+	h := NewHasher()
+	for _, hash := range hashes {
+		h.Int(hash)
+	}
+	return h.Hash()
+}
+
+// HashOf combines the hashes of several values, the equivalent of Java's Objects.hash, see Hasher.Value for the
+// supported types, e.g. `func (k myKey) Hash() int { return hashing.HashOf(k.name, k.id, k.parent) }`.
+func HashOf(values ...interface{}) int {
+	h := NewHasher()
+	for _, v := range values {
+		h.Value(v)
+	}
+	return h.Hash()
+}
+
+// HashString returns the hash of the contents of s.
+func HashString(s string) int {
+	return NewHasher().String(s).Hash()
+}
+
+// HashBytes returns the hash of the contents of b, which is the same as HashString(string(b)).
+func HashBytes(b []byte) int {
+	return NewHasher().Bytes(b).Hash()
+}
+
+// HashInts combines the hashes of several ints, which is the same as HashCombine.
+func HashInts(ints ...int) int {
+	return HashCombine(ints...)
+}
+
+// HashKeys combines the hashes of several keys, any of which may be nil, see Hasher.Key.
+func HashKeys(keys ...simhash.Key) int {
+	h := NewHasher()
+	for _, key := range keys {
+		h.Key(key)
+	}
+	return h.Hash()
+}
+
+// Mix is the 64 bit finaliser from MurmurHash3, which is a bijection, with good avalanche behaviour, and may be used to
+// implement simhash.Key64, e.g. `func (k myKey) Hash64() uint64 { return hashing.Mix(uint64(k)) }`.
+func Mix(h uint64) uint64 {
+	return hashbits.Mix(h)
+}
+
+// Fold converts a 64 bit hash to an int, combining the upper and lower halves, if an int is smaller than 64 bits, the
+// same as simhash uses to convert the result of simhash.Key64.Hash64.
+func Fold(h uint64) int {
+	return hashbits.Fold(h)
+}
+
+// FNV64a returns the 64 bit FNV-1a hash of s, which distributes short strings poorly, and should usually be mixed,
+// see Mix.
+func FNV64a(s string) uint64 {
+	return hashbits.String(s)
+}
+
+// FNV64aBytes is the same as FNV64a, without converting b to a string.
+func FNV64aBytes(b []byte) uint64 {
+	return hashbits.Bytes(b)
+}
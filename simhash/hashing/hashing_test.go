@@ -0,0 +1,117 @@
+/*
+   Copyright 2017 Joseph Cumines
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+
+ */
+
+package hashing
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/joeycumines/go-hashmap/simhash"
+)
+
+// testKeyStruct is a typical use of HashOf.
+type testKeyStruct struct {
+	name   string
+	id     int
+	parent simhash.Key
+}
+
+func (k testKeyStruct) Hash() int {
+	return HashOf(k.name, k.id, k.parent)
+}
+
+func (k testKeyStruct) Equals(other interface{}) bool {
+	o, ok := other.(testKeyStruct)
+	return ok && k.name == o.name && k.id == o.id &&
+		((nil == k.parent && nil == o.parent) || (nil != k.parent && k.parent.Equals(o.parent)))
+}
+
+func TestHashCombine(t *testing.T) {
+	if HashCombine(1, 2) != NewHasher().Int(1).Int(2).Hash() || HashCombine(1, 2) == HashCombine(2, 1) {
+		t.Fatal()
+	}
+	if HashCombine() != NewHasher().Hash() || HashCombine(0) == HashCombine() {
+		t.Fatal()
+	}
+}
+
+func TestHashOf(t *testing.T) {
+	if HashOf("a", 1, nil) != NewHasher().String("a").Int(1).Value(nil).Hash() {
+		t.Fatal()
+	}
+	if HashOf(nil) == HashOf() || HashOf(nil) == HashOf(nil, nil) || HashOf(nil, 1) == HashOf(1, nil) {
+		t.Fatal()
+	}
+	if HashOf(testKeyInt(1)) != HashKeys(testKeyInt(1)) {
+		t.Fatal()
+	}
+}
+
+func TestHashString(t *testing.T) {
+	if HashString("abc") != HashBytes([]byte("abc")) || HashString("abc") == HashString("abd") {
+		t.Fatal()
+	}
+	if HashString("") != HashBytes(nil) || HashString("") != HashOf("") {
+		t.Fatal()
+	}
+}
+
+func TestHashInts(t *testing.T) {
+	if HashInts(1, 2, 3) != HashCombine(1, 2, 3) || HashInts(1, 2, 3) != HashOf(1, 2, 3) {
+		t.Fatal()
+	}
+}
+
+func TestHashKeys(t *testing.T) {
+	if HashKeys(testKeyInt(1), nil) != NewHasher().Key(testKeyInt(1)).Key(nil).Hash() || HashKeys(nil) == HashKeys() {
+		t.Fatal()
+	}
+}
+
+func TestHashOf_map(t *testing.T) {
+	m := simhash.NewMap()
+	for i := 0; i < 100; i++ {
+		m.Put(testKeyStruct{"a", i, nil}, i)
+		m.Put(testKeyStruct{"b", i, testKeyInt(i)}, -i)
+	}
+	if 200 != m.Size() || 5 != m.Get(testKeyStruct{"a", 5, nil}).(int) || -5 != m.Get(testKeyStruct{"b", 5, testKeyInt(5)}).(int) {
+		t.Fatal(m.Size())
+	}
+	if nil != m.Get(testKeyStruct{"b", 5, nil}) {
+		t.Fatal()
+	}
+}
+
+func TestMix(t *testing.T) {
+	if 0 != Mix(0) || Mix(1) == Mix(2) || 0 == Mix(1)>>32 {
+		t.Fatal()
+	}
+	if 64 == strconv.IntSize && int(Mix(1)) != Fold(Mix(1)) {
+		t.Fatal()
+	}
+}
+
+func TestFNV64a(t *testing.T) {
+	if 0xaf63dc4c8601ec8c != FNV64a("a") || FNV64a("abc") != FNV64aBytes([]byte("abc")) {
+		t.Fatal()
+	}
+	// the hasher mixes in the FNV-1a hash of strings
+	if NewHasher().String("abc").Hash64() != NewHasher().Uint64(FNV64a("abc")).Hash64() {
+		t.Fatal()
+	}
+}
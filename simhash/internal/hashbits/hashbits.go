@@ -0,0 +1,86 @@
+/*
+   Copyright 2017 Joseph Cumines
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+
+ */
+
+// Package hashbits implements the hashing primitives shared by simhash and it's subpackages, which are exported for
+// implementations of simhash.Key by the hashing package, as simhash itself can't import it.
+package hashbits
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"strconv"
+)
+
+const (
+	// Offset is the initial state of a combined hash, which is not 0, so leading zero values still affect it.
+	Offset = 0x9e3779b97f4a7c15
+
+	// Nil is used in place of the hash of nil values, in combined hashes.
+	Nil = 0x2545f4914f6cdd1d
+
+	fnvOffset64 = 14695981039346656037
+	fnvPrime64  = 1099511628211
+)
+
+// Mix is the 64 bit finaliser from MurmurHash3, which is a bijection, with good avalanche behaviour.
+func Mix(h uint64) uint64 {
+	h ^= h >> 33
+	h *= 0xff51afd7ed558ccd
+	h ^= h >> 33
+	h *= 0xc4ceb9fe1a85ec53
+	h ^= h >> 33
+	return h
+}
+
+// Fold converts a 64 bit hash to an int, combining the upper and lower halves, if an int is smaller than 64 bits.
+func Fold(h uint64) int {
+	if strconv.IntSize < 64 {
+		h ^= h >> 32
+	}
+	return int(h)
+}
+
+// String hashes s using 64 bit FNV-1a, which distributes short strings poorly, unless it is also mixed.
+func String(s string) uint64 {
+	h := uint64(fnvOffset64)
+	for i := 0; i < len(s); i++ {
+		h ^= uint64(s[i])
+		h *= fnvPrime64
+	}
+	return h
+}
+
+// Bytes is the same as String, without converting b to a string.
+func Bytes(b []byte) uint64 {
+	h := uint64(fnvOffset64)
+	for _, c := range b {
+		h ^= uint64(c)
+		h *= fnvPrime64
+	}
+	return h
+}
+
+// RandomSeed returns a seed read from crypto/rand, for mixing into hashes, which will panic if no randomness is
+// available.
+func RandomSeed() uint64 {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); nil != err {
+		panic(fmt.Errorf("failed to read a random hash seed: %s", err.Error()))
+	}
+	return binary.LittleEndian.Uint64(b)
+}
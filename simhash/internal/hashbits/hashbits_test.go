@@ -0,0 +1,63 @@
+/*
+   Copyright 2017 Joseph Cumines
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+
+ */
+
+package hashbits
+
+import (
+	"math"
+	"strconv"
+	"testing"
+)
+
+func TestMix(t *testing.T) {
+	if 0 != Mix(0) || 0 == Mix(1)>>32 {
+		t.Fatal()
+	}
+	seen := make(map[uint64]bool)
+	for i := uint64(0); i < 1000; i++ {
+		h := Mix(i)
+		if true == seen[h] {
+			t.Fatal(i)
+		}
+		seen[h] = true
+	}
+}
+
+func TestFold(t *testing.T) {
+	if 64 == strconv.IntSize && -1 != Fold(math.MaxUint64) {
+		t.Fatal()
+	}
+	if 32 == strconv.IntSize && 0 != Fold(math.MaxUint64) {
+		t.Fatal()
+	}
+}
+
+func TestString(t *testing.T) {
+	// test vectors for 64 bit FNV-1a
+	if fnvOffset64 != String("") || 0xaf63dc4c8601ec8c != String("a") || 0x85944171f73967e8 != String("foobar") {
+		t.Fatal()
+	}
+	if String("ab") == String("ba") || String("abc") != Bytes([]byte("abc")) || Bytes(nil) != String("") {
+		t.Fatal()
+	}
+}
+
+func TestRandomSeed(t *testing.T) {
+	if RandomSeed() == RandomSeed() {
+		t.Fatal()
+	}
+}
@@ -17,11 +17,18 @@
 
 package simhash
 
+import (
+	"errors"
+)
+
 // Iterator provides an interface for iterating over the key-pairs of a map. It's value should initially start as nil,
 // or uninitialized, and on the first call to either Next or Previous should determine the initial direction.
 // After being initialized, Next (or Previous) can be used increment or de-increment the pointer, which will always
 // stop at the last (or first) item in the iteration. The iterator can change direction, but will not move for the
 // first call to the step in the opposite direction.
+// Iterators over maps that are not safe for concurrent use are usually fail-fast, the same as in Java, and will panic
+// with a *ConcurrentModificationError if the map has keys added or removed after the iterator was created, as it
+// would otherwise skip or repeat pairs. Replacing the value of an existing key is not a structural modification.
 type Iterator interface {
 	// Pair will return the current pair in the iteration, initially nil.
 	Pair() Pair
@@ -41,6 +48,33 @@ type Iterator interface {
 	Previous() bool
 }
 
+// MutableIterator is an Iterator that can also modify the current pair, the same as Iterator.remove and
+// Map.Entry.setValue in Java. Modifications made using the iterator will not cause it to fail, and will not cause it to
+// skip or repeat any pairs. Iterators may be type asserted to MutableIterator, which is implemented by the iterators of
+// every Map returned by this package, but not Set or BiMap.
+type MutableIterator interface {
+	Iterator
+
+	// Remove removes the current pair from the map, and returns it's value, after which Pair, Key, and Value will
+	// return nil until the iterator is moved. It will panic if there is no current pair.
+	Remove() Value
+
+	// SetValue replaces the value of the current pair, which is not a structural modification, and returns the
+	// previous value. It will panic if there is no current pair.
+	SetValue(value Value) Value
+}
+
+// errNoCurrentPair is the panic value for MutableIterator methods, when the iterator is not at a pair.
+var errNoCurrentPair = errors.New("the iterator has no current pair")
+
+// ConcurrentModificationError is the panic value used by a fail-fast Iterator, if the map was structurally modified
+// after the iterator was created, by anything other than the iterator itself.
+type ConcurrentModificationError struct{}
+
+func (e *ConcurrentModificationError) Error() string {
+	return "the map was modified during iteration"
+}
+
 type iterator struct {
 	m        *hashMap
 	hList    []int
@@ -49,6 +83,7 @@ type iterator struct {
 	i        int
 	forwards bool
 	active   bool
+	modCount int
 }
 
 func (it *iterator) Pair() Pair {
@@ -69,7 +104,14 @@ func (it *iterator) Key() Key {
 	return it.pair.Key()
 }
 
+func (it *iterator) check() {
+	if false == it.m.weakIterators && it.modCount != it.m.modCount {
+		panic(&ConcurrentModificationError{})
+	}
+}
+
 func (it *iterator) increment(forwards bool) bool {
+	it.check()
 	if 0 == len(it.hList) {
 		return false
 	}
@@ -138,3 +180,181 @@ func (it *iterator) Next() bool {
 func (it *iterator) Previous() bool {
 	return it.increment(false)
 }
+
+// lookup returns the location of the current pair, panicking if there isn't one.
+func (it *iterator) lookup() (int, int) {
+	it.check()
+	if nil == it.pair {
+		panic(errNoCurrentPair)
+	}
+	h, i, ok := it.m.lookup(it.pair.Key())
+	if false == ok {
+		panic(errNoCurrentPair)
+	}
+	return h, i
+}
+
+// Remove accounts for removeAt moving the last pair in the bucket into the removed slot, which must be visited next,
+// if iterating forwards. Backwards, the moved pair has already been visited, and the slot is behind the iterator.
+func (it *iterator) Remove() Value {
+	h, i := it.lookup()
+	if true == it.forwards && it.h >= 0 && it.h < len(it.hList) && h == it.hList[it.h] && i < it.i {
+		it.i--
+	}
+	v := it.m.removeAt(h, i)
+	it.modCount = it.m.modCount
+	it.pair = nil
+	return v
+}
+
+func (it *iterator) SetValue(value Value) Value {
+	h, i := it.lookup()
+	v := it.m.m[h][i].Value()
+	it.pair = NewPair(it.pair.Key(), value)
+	it.m.replaceAt(h, i, it.pair)
+	return v
+}
+
+// sliceIterator implements Iterator over a slice of pairs, for map implementations that cannot safely (or cheaply)
+// iterate their internal state directly, where nil pairs are skipped. It follows the same stepping rules as iterator.
+// The slice may be virtual, accessed using length and at, which are called on each step, after check, if it is set.
+type sliceIterator struct {
+	length   func() int
+	at       func(i int) Pair
+	check    func()
+	pair     Pair
+	i        int
+	forwards bool
+	active   bool
+}
+
+// newSliceIterator returns an iterator over a snapshot of pairs.
+func newSliceIterator(pairs []Pair) *sliceIterator {
+	return &sliceIterator{
+		length: func() int {
+			return len(pairs)
+		},
+		at: func(i int) Pair {
+			return pairs[i]
+		},
+		forwards: true,
+	}
+}
+
+func (it *sliceIterator) Pair() Pair {
+	return it.pair
+}
+
+func (it *sliceIterator) Value() Value {
+	if nil == it.pair {
+		return nil
+	}
+	return it.pair.Value()
+}
+
+func (it *sliceIterator) Key() Key {
+	if nil == it.pair {
+		return nil
+	}
+	return it.pair.Key()
+}
+
+func (it *sliceIterator) increment(forwards bool) bool {
+	if nil != it.check {
+		it.check()
+	}
+	length := it.length()
+	if 0 == length {
+		return false
+	}
+	done := func() bool {
+		return it.i >= length || it.i < 0
+	}
+	inc := 1
+	if false == forwards {
+		inc = -1
+	}
+	reset := func() {
+		it.i = 0
+		if inc < 0 {
+			it.i = length - 1
+		}
+	}
+	if false == it.active {
+		it.active = true
+		it.forwards = forwards
+		reset()
+	}
+	if forwards != it.forwards {
+		if true == done() {
+			reset()
+		} else {
+			it.i += inc
+		}
+	}
+	it.forwards = forwards
+	for false == done() {
+		pair := it.at(it.i)
+		it.i += inc
+		if nil == pair {
+			continue
+		}
+		it.pair = pair
+		return true
+	}
+	return false
+}
+
+func (it *sliceIterator) Next() bool {
+	return it.increment(true)
+}
+
+func (it *sliceIterator) Previous() bool {
+	return it.increment(false)
+}
+
+// readOnlyIterator hides the MutableIterator methods of an iterator.
+type readOnlyIterator struct {
+	Iterator
+}
+
+// mutableSliceIterator is a sliceIterator that modifies the map using remove and setValue, which must not affect
+// the positions of the pairs that are iterated.
+type mutableSliceIterator struct {
+	*sliceIterator
+	remove   func(key Key) Value
+	setValue func(key Key, value Value) Value
+}
+
+func (it *mutableSliceIterator) current() Key {
+	if nil != it.check {
+		it.check()
+	}
+	if nil == it.pair {
+		panic(errNoCurrentPair)
+	}
+	return it.pair.Key()
+}
+
+func (it *mutableSliceIterator) Remove() Value {
+	v := it.remove(it.current())
+	it.pair = nil
+	return v
+}
+
+func (it *mutableSliceIterator) SetValue(value Value) Value {
+	key := it.current()
+	v := it.setValue(key, value)
+	it.pair = NewPair(key, value)
+	return v
+}
+
+// newSnapshotIterator returns an iterator over a snapshot of pairs, which removes or replaces keys in m, for maps
+// which do not otherwise support mutable iteration.
+func newSnapshotIterator(m Map, pairs []Pair) *mutableSliceIterator {
+	return &mutableSliceIterator{
+		sliceIterator: newSliceIterator(pairs),
+		remove:        m.Remove,
+		setValue:      m.Put,
+	}
+}
@@ -265,3 +265,295 @@ func TestIterator(t *testing.T) {
 		t.Fatal()
 	}
 }
+
+func TestSliceIterator(t *testing.T) {
+	one, two, three := NewPair(testKeyInt(1), 1), NewPair(testKeyInt(2), 2), NewPair(testKeyInt(3), 3)
+	it := newSliceIterator([]Pair{one, nil, two, three})
+	if nil != it.Pair() || nil != it.Key() || nil != it.Value() {
+		t.Fatal()
+	}
+	expect := func(ok bool, pair Pair) {
+		t.Helper()
+		if true != ok || pair != it.Pair() {
+			t.Fatalf("unexpected: %v", it.Pair())
+		}
+	}
+	expect(it.Next(), one)
+	expect(it.Next(), two)
+	// changing direction will not move
+	expect(it.Previous(), two)
+	expect(it.Previous(), one)
+	if false != it.Previous() || one != it.Pair() {
+		t.Fatal()
+	}
+	expect(it.Next(), one)
+	expect(it.Next(), two)
+	expect(it.Next(), three)
+	if false != it.Next() || three != it.Pair() || 3 != it.Value().(int) || 3 != it.Key().Hash() {
+		t.Fatal()
+	}
+	expect(it.Previous(), three)
+	expect(it.Previous(), two)
+	// starting backwards
+	it = newSliceIterator([]Pair{one, two})
+	expect(it.Previous(), two)
+	expect(it.Previous(), one)
+	if true == newSliceIterator(nil).Next() || true == newSliceIterator([]Pair{nil}).Previous() {
+		t.Fatal()
+	}
+}
+
+// expectConcurrentModification calls fn, failing unless it panics with a *ConcurrentModificationError.
+func expectConcurrentModification(t *testing.T, fn func() bool) {
+	t.Helper()
+	defer func() {
+		t.Helper()
+		r := recover()
+		if err, ok := r.(*ConcurrentModificationError); false == ok || "the map was modified during iteration" != err.Error() {
+			t.Fatalf("unexpected panic: %v", r)
+		}
+	}()
+	fn()
+}
+
+func TestIterator_concurrentModification(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		newMap func() Map
+	}{
+		{"NewMap", func() Map { return NewMap() }},
+		{"WithOpenAddressing", func() Map { return NewMap(WithOpenAddressing()) }},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			m := tc.newMap()
+			for i := 0; i < 10; i++ {
+				m.Put(testKeyInt(i), i)
+			}
+			it := m.Iterator()
+			if false == it.Next() {
+				t.Fatal()
+			}
+			// replacing values, and removing keys that don't exist, are not structural modifications
+			m.Put(it.Key(), -1)
+			m.Remove(testKeyInt(-1))
+			if -1 != m.Get(it.Key()) || false == it.Previous() {
+				t.Fatal()
+			}
+			m.Put(testKeyInt(10), 10)
+			expectConcurrentModification(t, it.Next)
+			expectConcurrentModification(t, it.Previous)
+			it = m.Iterator()
+			m.Remove(testKeyInt(3))
+			expectConcurrentModification(t, it.Next)
+			// an exhausted iterator still fails
+			it = m.Iterator()
+			for true == it.Next() {
+			}
+			m.Remove(testKeyInt(4))
+			expectConcurrentModification(t, it.Previous)
+			// as do iterators over an empty map
+			m = tc.newMap()
+			it = m.Iterator()
+			m.Put(nil, 1)
+			expectConcurrentModification(t, it.Next)
+		})
+	}
+}
+
+func TestIterator_weaklyConsistent(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		newMap func() Map
+	}{
+		{"NewMap", func() Map { return NewMap(WithWeaklyConsistentIterators()) }},
+		{"WithOpenAddressing", func() Map { return NewMap(WithOpenAddressing(), WithWeaklyConsistentIterators()) }},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			m := tc.newMap()
+			for i := 0; i < 100; i++ {
+				m.Put(testKeyStruct{i % 7, i}, i)
+			}
+			// pairs may be skipped, but not repeated
+			seen := make(map[Key]bool)
+			removed := 0
+			for it := m.Iterator(); true == it.Next(); {
+				if true == seen[it.Key()] || it.Value() != m.Get(it.Key()) {
+					t.Fatal(it.Key())
+				}
+				seen[it.Key()] = true
+				if 0 == it.Value().(int)%2 {
+					m.Remove(it.Key())
+					removed++
+				}
+			}
+			if 0 == removed || 100-removed != m.Size() {
+				t.Fatal(len(seen), m.Size())
+			}
+		})
+	}
+}
+
+func TestIterator_concurrentModificationWrappers(t *testing.T) {
+	s := NewSet(testKeyInt(1), testKeyInt(2))
+	it := s.Iterator()
+	s.Add(testKeyInt(3))
+	expectConcurrentModification(t, it.Next)
+	b := NewBiMap()
+	b.Put(testKeyInt(1), testKeyInt(2))
+	it = b.Inverse().Iterator()
+	b.Put(testKeyInt(3), testKeyInt(4))
+	expectConcurrentModification(t, it.Next)
+	// snapshot iterators are not affected
+	for _, m := range []Map{NewLinkedMap(), NewSortedMap(), NewConcurrentMap()} {
+		m.Put(testKeyInt(1), 1)
+		it = m.Iterator()
+		m.Put(testKeyInt(2), 2)
+		if false == it.Next() || true == it.Next() {
+			t.Fatal(m)
+		}
+	}
+}
+
+// expectNoCurrentPair calls fn, failing unless it panics with errNoCurrentPair.
+func expectNoCurrentPair(t *testing.T, fn func()) {
+	t.Helper()
+	defer func() {
+		t.Helper()
+		if r := recover(); errNoCurrentPair != r {
+			t.Fatalf("unexpected panic: %v", r)
+		}
+	}()
+	fn()
+}
+
+func TestMutableIterator(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		newMap func() Map
+	}{
+		{"NewMap", func() Map { return NewMap() }},
+		{"WithWeaklyConsistentIterators", func() Map { return NewMap(WithWeaklyConsistentIterators()) }},
+		{"WithOpenAddressing", func() Map { return NewMap(WithOpenAddressing()) }},
+		{"NewLinkedMap", NewLinkedMap},
+		{"NewAccessOrderedLinkedMap", NewAccessOrderedLinkedMap},
+		{"NewLRU", func() Map { return NewLRU(100, nil) }},
+		{"NewSortedMap", func() Map { return NewSortedMap() }},
+		{"NewConcurrentMap", func() Map { return NewConcurrentMap() }},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			for _, forwards := range []bool{true, false} {
+				m := tc.newMap()
+				// every key collides, so hashMap will move pairs within the same bucket on each removal
+				for i := 0; i < 40; i++ {
+					m.Put(testKeyCollision(i), i)
+				}
+				it := m.Iterator().(MutableIterator)
+				expectNoCurrentPair(t, func() { it.Remove() })
+				expectNoCurrentPair(t, func() { it.SetValue(1) })
+				step := it.Next
+				if false == forwards {
+					step = it.Previous
+				}
+				seen := make(map[Key]bool)
+				for true == step() {
+					k := it.Key()
+					if true == seen[k] {
+						t.Fatalf("%v returned twice", k)
+					}
+					seen[k] = true
+					i := it.Value().(int)
+					if 0 == i%2 {
+						if v := it.Remove(); i != v || nil != it.Pair() || nil != it.Key() || nil != it.Value() {
+							t.Fatal(v, it.Pair())
+						}
+						expectNoCurrentPair(t, func() { it.Remove() })
+						expectNoCurrentPair(t, func() { it.SetValue(1) })
+						continue
+					}
+					if v := it.SetValue(i * 10); i != v || i*10 != it.Value() || k != it.Key() {
+						t.Fatal(v, it.Pair())
+					}
+				}
+				if 40 != len(seen) || 20 != m.Size() {
+					t.Fatal(len(seen), m.Size())
+				}
+				for i := 0; i < 40; i++ {
+					if v := m.Get(testKeyCollision(i)); (0 == i%2 && nil != v) || (1 == i%2 && i*10 != v) {
+						t.Fatal(i, v)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestMutableIterator_changeDirection(t *testing.T) {
+	m := NewMap()
+	for i := 0; i < 5; i++ {
+		m.Put(testKeyCollision(i), i)
+	}
+	it := m.Iterator().(MutableIterator)
+	keys := make([]Key, 0)
+	for true == it.Next() {
+		keys = append(keys, it.Key())
+	}
+	it = m.Iterator().(MutableIterator)
+	it.Next()
+	it.Next()
+	it.Next()
+	// removing the third pair moves the last pair into it's slot, which is next, and the previous pair is still second
+	it.Remove()
+	if false == it.Next() || keys[4] != it.Key() {
+		t.Fatal(it.Key())
+	}
+	if false == it.Previous() || keys[4] != it.Key() || false == it.Previous() || keys[1] != it.Key() {
+		t.Fatal(it.Key())
+	}
+	it.Remove()
+	if false == it.Previous() || keys[0] != it.Key() || true == it.Previous() {
+		t.Fatal(it.Key())
+	}
+	it.Remove()
+	if false == it.Next() || keys[4] != it.Key() || false == it.Next() || keys[3] != it.Key() || true == it.Next() {
+		t.Fatal(it.Key())
+	}
+	if 2 != m.Size() {
+		t.Fatal(m.Size())
+	}
+}
+
+func TestMutableIterator_readOnly(t *testing.T) {
+	if _, ok := NewSet().Iterator().(MutableIterator); true == ok {
+		t.Fatal()
+	}
+	if _, ok := NewBiMap().Iterator().(MutableIterator); true == ok {
+		t.Fatal()
+	}
+	if _, ok := NewBiMap().Inverse().Iterator().(MutableIterator); true == ok {
+		t.Fatal()
+	}
+}
+
+func TestMutableIterator_linkedAccessOrder(t *testing.T) {
+	m := NewAccessOrderedLinkedMap()
+	for i := 0; i < 3; i++ {
+		m.Put(testKeyInt(i), i)
+	}
+	it := m.Iterator().(MutableIterator)
+	it.Next()
+	if 0 != it.SetValue(10) || 10 != m.Get(testKeyInt(0)) {
+		t.Fatal()
+	}
+	m.Put(testKeyInt(1), 11)
+	it.Next()
+	m.Remove(testKeyInt(1))
+	// the key was removed from the map after the snapshot was taken, so it won't be added back
+	if nil != it.SetValue(12) || true == m.Contains(testKeyInt(1)) {
+		t.Fatal()
+	}
+	it.Next()
+	it.SetValue(20)
+	if keys := m.Keys(); 2 != len(keys) || testKeyInt(2) != keys[0] || testKeyInt(0) != keys[1] {
+		t.Fatal(keys)
+	}
+}
@@ -0,0 +1,82 @@
+/*
+   Copyright 2017 Joseph Cumines
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+
+ */
+
+package javahash
+
+// List returns the equivalent of Java's List.hashCode, given the hashes of each element, where the hash of a null
+// element is 0. This is also the same as Arrays.hashCode for an Object[], and therefore Objects.hash.
+func List(hashes ...int32) int32 {
+	h := int32(1)
+	for _, v := range hashes {
+		h = 31*h + v
+	}
+	return h
+}
+
+// Ints returns the equivalent of Java's Arrays.hashCode for an int[], which is 1 for an empty array.
+func Ints(values ...int32) int32 {
+	return List(values...)
+}
+
+// Longs returns the equivalent of Java's Arrays.hashCode for a long[], which is 1 for an empty array.
+func Longs(values ...int64) int32 {
+	h := int32(1)
+	for _, v := range values {
+		h = 31*h + Long(v)
+	}
+	return h
+}
+
+// Bytes returns the equivalent of Java's Arrays.hashCode for a byte[], where Java's bytes are signed, which is 1 for
+// an empty array.
+func Bytes(values []byte) int32 {
+	h := int32(1)
+	for _, v := range values {
+		h = 31*h + int32(int8(v))
+	}
+	return h
+}
+
+// Strings returns the equivalent of Java's List.hashCode, for a list of strings, none of which are null.
+func Strings(values ...string) int32 {
+	h := int32(1)
+	for _, v := range values {
+		h = 31*h + String(v)
+	}
+	return h
+}
+
+// Set returns the equivalent of Java's Set.hashCode, given the hashes of each element, which is their sum, where the
+// hash of a null element is 0.
+func Set(hashes ...int32) int32 {
+	var h int32
+	for _, v := range hashes {
+		h += v
+	}
+	return h
+}
+
+// MapEntry returns the equivalent of Java's Map.Entry.hashCode, given the hashes of the key and value.
+func MapEntry(key, value int32) int32 {
+	return key ^ value
+}
+
+// Map returns the equivalent of Java's Map.hashCode, given the hashes of each entry, see MapEntry, which is the sum
+// of the hashes of the entries.
+func Map(entries ...int32) int32 {
+	return Set(entries...)
+}
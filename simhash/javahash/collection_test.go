@@ -0,0 +1,81 @@
+/*
+   Copyright 2017 Joseph Cumines
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+
+ */
+
+package javahash
+
+import (
+	"testing"
+)
+
+func TestList(t *testing.T) {
+	// List.of(1, 2, 3).hashCode()
+	if 30817 != List(Integer(1), Integer(2), Integer(3)) || 1 != List() {
+		t.Fatal()
+	}
+	// Arrays.asList(null, null).hashCode()
+	if 961 != List(0, 0) {
+		t.Fatal()
+	}
+	// Objects.hash("a", 1, true)
+	if 31*(31*(31+97)+1)+1231 != List(String("a"), Integer(1), Boolean(true)) {
+		t.Fatal()
+	}
+}
+
+func TestInts(t *testing.T) {
+	if 30817 != Ints(1, 2, 3) || 1 != Ints() || 31 != Ints(0) {
+		t.Fatal()
+	}
+}
+
+func TestLongs(t *testing.T) {
+	if 31 != Longs(-1) || 30817 != Longs(1, 2, 3) || 1 != Longs() || 32 != Longs(1<<32) {
+		t.Fatal()
+	}
+}
+
+func TestBytes(t *testing.T) {
+	if 30 != Bytes([]byte{0xff}) || 30817 != Bytes([]byte{1, 2, 3}) || 1 != Bytes(nil) {
+		t.Fatal()
+	}
+}
+
+func TestStrings(t *testing.T) {
+	if 4066 != Strings("a", "b") || 1 != Strings() || List(String("hello")) != Strings("hello") {
+		t.Fatal()
+	}
+}
+
+func TestSet(t *testing.T) {
+	// Set.of("a", "b").hashCode()
+	if 195 != Set(String("a"), String("b")) || 0 != Set() {
+		t.Fatal()
+	}
+}
+
+func TestMapEntry(t *testing.T) {
+	if 96 != MapEntry(String("a"), Integer(1)) {
+		t.Fatal()
+	}
+}
+
+func TestMap(t *testing.T) {
+	// Map.of("a", 1, "b", 2).hashCode()
+	if 96+96 != Map(MapEntry(String("a"), Integer(1)), MapEntry(String("b"), Integer(2))) || 0 != Map() {
+		t.Fatal()
+	}
+}
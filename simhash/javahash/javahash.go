@@ -0,0 +1,51 @@
+/*
+   Copyright 2017 Joseph Cumines
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+
+ */
+
+// Package javahash reproduces the exact hashCode algorithms used by Java, for keys that must hash the same as their
+// equivalent in a Java service, e.g. to agree on partitioning. Each function returns an int32, the same as a Java
+// int, which may be converted to an int, to implement simhash.Key.
+// Note that simhash.Map does not apply the spreading used by java.util.HashMap, see Spread.
+package javahash
+
+import (
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// String returns the equivalent of Java's String.hashCode, for a string containing the same characters, which is
+// computed over the UTF-16 encoding of s. Each byte of any invalid UTF-8 is treated as U+FFFD, the same as ranging
+// over s, which may not match the String that Java would decode from the same bytes.
+func String(s string) int32 {
+	var h int32
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		i += size
+		if r1, r2 := utf16.EncodeRune(r); utf8.RuneError != r1 {
+			h = 31*h + r1
+			h = 31*h + r2
+			continue
+		}
+		h = 31*h + r
+	}
+	return h
+}
+
+// Spread returns the hash used by java.util.HashMap to select a bucket, for a key with the hash h, which xors the
+// upper 16 bits into the lower 16 bits, the equivalent of `h ^ (h >>> 16)`.
+func Spread(h int32) int32 {
+	return h ^ int32(uint32(h)>>16)
+}
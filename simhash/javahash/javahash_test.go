@@ -0,0 +1,64 @@
+/*
+   Copyright 2017 Joseph Cumines
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+
+ */
+
+package javahash
+
+import (
+	"math"
+	"testing"
+)
+
+func TestString(t *testing.T) {
+	for _, tc := range []struct {
+		s        string
+		expected int32
+	}{
+		{"", 0},
+		{"a", 97},
+		{"hello", 99162322},
+		{"Aa", 2112},
+		{"BB", 2112},
+		{"polygenelubricants", math.MinInt32},
+		// U+00E9, which is a single UTF-16 code unit, but two UTF-8 bytes
+		{"é", 233},
+		// U+1F600, which is a surrogate pair, D83D DE00
+		{"\U0001F600", 1772899},
+		// each invalid byte is U+FFFD
+		{"\xff", 65533},
+		{"\xff\xfe", 31*65533 + 65533},
+	} {
+		if actual := String(tc.s); tc.expected != actual {
+			t.Errorf("%q: expected %d, actual %d", tc.s, tc.expected, actual)
+		}
+	}
+}
+
+func TestSpread(t *testing.T) {
+	for _, tc := range []struct {
+		h, expected int32
+	}{
+		{0, 0},
+		{99162322, 99163451},
+		{-1, -65536},
+		{math.MinInt32, math.MinInt32 + 0x8000},
+		{0x10000, 0x10001},
+	} {
+		if actual := Spread(tc.h); tc.expected != actual {
+			t.Errorf("%d: expected %d, actual %d", tc.h, tc.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+/*
+   Copyright 2017 Joseph Cumines
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+
+ */
+
+package javahash
+
+import (
+	"math"
+)
+
+// Boolean returns the equivalent of Java's Boolean.hashCode, which is 1231 for true and 1237 for false.
+func Boolean(v bool) int32 {
+	if true == v {
+		return 1231
+	}
+	return 1237
+}
+
+// Byte returns the equivalent of Java's Byte.hashCode, which is the value itself.
+func Byte(v int8) int32 {
+	return int32(v)
+}
+
+// Short returns the equivalent of Java's Short.hashCode, which is the value itself.
+func Short(v int16) int32 {
+	return int32(v)
+}
+
+// Character returns the equivalent of Java's Character.hashCode, which is the UTF-16 code unit itself.
+func Character(v uint16) int32 {
+	return int32(v)
+}
+
+// Integer returns the equivalent of Java's Integer.hashCode, which is the value itself.
+func Integer(v int32) int32 {
+	return v
+}
+
+// Long returns the equivalent of Java's Long.hashCode, which xors the upper and lower 32 bits.
+func Long(v int64) int32 {
+	return int32(v ^ int64(uint64(v)>>32))
+}
+
+// Float returns the equivalent of Java's Float.hashCode, which is the IEEE 754 representation of the value, where
+// every NaN has the same representation.
+func Float(v float32) int32 {
+	if v != v {
+		return 0x7fc00000
+	}
+	return int32(math.Float32bits(v))
+}
+
+// Double returns the equivalent of Java's Double.hashCode, which is the Long hash of the IEEE 754 representation of
+// the value, where every NaN has the same representation.
+func Double(v float64) int32 {
+	if v != v {
+		return Long(0x7ff8000000000000)
+	}
+	return Long(int64(math.Float64bits(v)))
+}
@@ -0,0 +1,111 @@
+/*
+   Copyright 2017 Joseph Cumines
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+
+ */
+
+package javahash
+
+import (
+	"math"
+	"testing"
+)
+
+func TestBoolean(t *testing.T) {
+	if 1231 != Boolean(true) || 1237 != Boolean(false) {
+		t.Fatal()
+	}
+}
+
+func TestByte(t *testing.T) {
+	if -1 != Byte(-1) || 127 != Byte(127) {
+		t.Fatal()
+	}
+}
+
+func TestShort(t *testing.T) {
+	if -1 != Short(-1) || math.MaxInt16 != Short(math.MaxInt16) {
+		t.Fatal()
+	}
+}
+
+func TestCharacter(t *testing.T) {
+	if 97 != Character('a') || 0xffff != Character(0xffff) {
+		t.Fatal()
+	}
+}
+
+func TestInteger(t *testing.T) {
+	if -1 != Integer(-1) || math.MinInt32 != Integer(math.MinInt32) {
+		t.Fatal()
+	}
+}
+
+func TestLong(t *testing.T) {
+	for _, tc := range []struct {
+		v        int64
+		expected int32
+	}{
+		{0, 0},
+		{1, 1},
+		{-1, 0},
+		{1 << 32, 1},
+		{math.MaxInt64, math.MinInt32},
+		{math.MinInt64, math.MinInt32},
+		{4294967296 + 5, 4},
+	} {
+		if actual := Long(tc.v); tc.expected != actual {
+			t.Errorf("%d: expected %d, actual %d", tc.v, tc.expected, actual)
+		}
+	}
+}
+
+func TestFloat(t *testing.T) {
+	for _, tc := range []struct {
+		v        float32
+		expected int32
+	}{
+		{0, 0},
+		{float32(math.Copysign(0, -1)), math.MinInt32},
+		{1, 1065353216},
+		{-1, -1082130432},
+		{float32(math.Inf(1)), 2139095040},
+		{float32(math.NaN()), 2143289344},
+		{math.Float32frombits(0xffc00001), 2143289344},
+	} {
+		if actual := Float(tc.v); tc.expected != actual {
+			t.Errorf("%v: expected %d, actual %d", tc.v, tc.expected, actual)
+		}
+	}
+}
+
+func TestDouble(t *testing.T) {
+	for _, tc := range []struct {
+		v        float64
+		expected int32
+	}{
+		{0, 0},
+		{math.Copysign(0, -1), math.MinInt32},
+		{1, 1072693248},
+		{-1, -1074790400},
+		{0.1, -1717986918 ^ 1069128089},
+		{math.Inf(1), 2146435072},
+		{math.NaN(), 2146959360},
+		{math.Float64frombits(0xfff0000000000001), 2146959360},
+	} {
+		if actual := Double(tc.v); tc.expected != actual {
+			t.Errorf("%v: expected %d, actual %d", tc.v, tc.expected, actual)
+		}
+	}
+}
@@ -17,6 +17,10 @@
 
 package simhash
 
+import (
+	"github.com/joeycumines/go-hashmap/simhash/internal/hashbits"
+)
+
 // Key is an interface based on the core Java Object, which forms the basis for the hashmap implementation.
 // See https://www.sitepoint.com/how-to-implement-javas-hashcode-correctly/ or look around for details on implementing
 // this.
@@ -24,3 +28,25 @@ type Key interface {
 	Hash() int
 	Equals(other interface{}) bool
 }
+
+// Key64 may optionally be implemented by a Key to provide a 64 bit hash, which every Map implementation in this
+// package will prefer over Hash, reducing collisions in large maps. Equal keys must return equal 64 bit hashes, and
+// keys of different types that may be equal must agree on whether they implement Key64.
+type Key64 interface {
+	Key
+	Hash64() uint64
+}
+
+// DeriveHash64 returns the 64 bit hash of key, which will be the result of Hash64 if key implements Key64, otherwise
+// it is derived from Hash, by mixing it with a bijective finaliser so that it's bits are spread across the full 64
+// bits. A nil key has a hash of 0. It is intended for keys which wrap or compose legacy keys, and must implement
+// Key64, as it adds no entropy.
+func DeriveHash64(key Key) uint64 {
+	if nil == key {
+		return 0
+	}
+	if k, ok := key.(Key64); true == ok {
+		return k.Hash64()
+	}
+	return hashbits.Mix(uint64(key.Hash()))
+}
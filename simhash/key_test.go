@@ -17,7 +17,12 @@
 
 package simhash
 
-import "strconv"
+import (
+	"strconv"
+	"testing"
+
+	"github.com/joeycumines/go-hashmap/simhash/internal/hashbits"
+)
 
 type testKeyInt int
 
@@ -26,7 +31,12 @@ func (k testKeyInt) Hash() int {
 }
 
 func (k testKeyInt) Equals(other interface{}) bool {
-	return int(k) == int(other.(testKeyInt))
+	o, ok := other.(testKeyInt)
+	return ok && k == o
+}
+
+func (k testKeyInt) Compare(other interface{}) int {
+	return int(k) - int(other.(testKeyInt))
 }
 
 type testKeyStruct struct {
@@ -45,3 +55,136 @@ func (k testKeyStruct) Equals(other interface{}) bool {
 func (k testKeyStruct) String() string {
 	return strconv.Itoa(k.val)
 }
+
+// testKeyCollision always has the same hash, and is Comparable.
+type testKeyCollision int
+
+func (k testKeyCollision) Hash() int {
+	return 7
+}
+
+func (k testKeyCollision) Equals(other interface{}) bool {
+	o, ok := other.(testKeyCollision)
+	return ok && o == k
+}
+
+func (k testKeyCollision) Compare(other interface{}) int {
+	return int(k) - int(other.(testKeyCollision))
+}
+
+// testKeyCollisionString has the same hash as testKeyCollision, but is not Comparable.
+type testKeyCollisionString string
+
+func (k testKeyCollisionString) Hash() int {
+	return 7
+}
+
+func (k testKeyCollisionString) Equals(other interface{}) bool {
+	o, ok := other.(testKeyCollisionString)
+	return ok && o == k
+}
+
+// testKey64 has a 64 bit hash, where the upper bits are the value, and the lower bits are always 0.
+type testKey64 int
+
+func (k testKey64) Hash() int {
+	return 0
+}
+
+func (k testKey64) Hash64() uint64 {
+	return uint64(k) << 32
+}
+
+func (k testKey64) Equals(other interface{}) bool {
+	o, ok := other.(testKey64)
+	return ok && k == o
+}
+
+func TestDeriveHash64(t *testing.T) {
+	if 0 != DeriveHash64(nil) || 5<<32 != DeriveHash64(testKey64(5)) {
+		t.Fatal()
+	}
+	if hashbits.Mix(5) != DeriveHash64(testKeyInt(5)) || hashbits.Mix(uint64(0xFFFFFFFFFFFFFFFB)) != DeriveHash64(testKeyInt(-5)) {
+		t.Fatal()
+	}
+	if DeriveHash64(testKeyInt(5)) == DeriveHash64(testKeyInt(6)) || 0 == DeriveHash64(testKeyInt(5))>>32 {
+		t.Fatal()
+	}
+}
+
+func TestHashOf(t *testing.T) {
+	if 0 != hashOf(nil) || 5 != hashOf(testKeyInt(5)) || -5 != hashOf(testKeyInt(-5)) {
+		t.Fatal()
+	}
+	if 0 != hash64Of(nil) || 5 != hash64Of(testKeyInt(5)) || uint64(0xFFFFFFFFFFFFFFFB) != hash64Of(testKeyInt(-5)) {
+		t.Fatal()
+	}
+	if 5<<32 != hash64Of(testKey64(5)) || 0 == hashOf(testKey64(5)) || hashOf(testKey64(5)) == hashOf(testKey64(6)) {
+		t.Fatal()
+	}
+}
+
+func TestKey64_maps(t *testing.T) {
+	for name, m := range map[string]Map{
+		"hashMap":       NewMap(),
+		"swissMap":      NewMap(WithOpenAddressing()),
+		"seeded":        NewMap(WithRandomHashSeed()),
+		"concurrentMap": NewConcurrentMap(),
+		"linkedMap":     NewLinkedMap(),
+	} {
+		for i := 0; i < 100; i++ {
+			m.Put(testKey64(i), i)
+		}
+		m.Put(nil, -1)
+		if 101 != m.Size() || -1 != m.Get(nil).(int) {
+			t.Fatal(name, m.Size())
+		}
+		for i := 0; i < 100; i++ {
+			if i != m.Get(testKey64(i)).(int) {
+				t.Fatal(name, i)
+			}
+		}
+		for i := 0; i < 100; i += 2 {
+			if i != m.Remove(testKey64(i)).(int) {
+				t.Fatal(name, i)
+			}
+		}
+		if 51 != m.Size() || true == m.Contains(testKey64(2)) || true != m.Contains(testKey64(3)) {
+			t.Fatal(name, m.Size())
+		}
+	}
+	// every key has the same Hash, but a distinct Hash64
+	m := NewMap().(*hashMap)
+	for i := 0; i < 100; i++ {
+		m.Put(testKey64(i), i)
+	}
+	if 100 != len(m.m) {
+		t.Fatal(len(m.m))
+	}
+	c := NewConcurrentMap().(*concurrentMap)
+	if c.shard(testKey64(1)) == c.shard(testKey64(2)) {
+		t.Fatal()
+	}
+	p := NewPersistentMap()
+	for i := 0; i < 100; i++ {
+		p = p.With(testKey64(i), i)
+	}
+	if 100 != p.Size() || 50 != p.Get(testKey64(50)).(int) || 99 != p.Without(testKey64(50)).Size() ||
+		hamtHash(testKey64(1)) == hamtHash(testKey64(2)) {
+		t.Fatal(p.Size())
+	}
+}
+
+// testKeyPtr only implements Key as a pointer, which must not be nil.
+type testKeyPtr struct {
+	val int
+}
+
+func (k *testKeyPtr) Hash() int {
+	return k.val
+}
+
+func (k *testKeyPtr) Equals(other interface{}) bool {
+	o, ok := other.(*testKeyPtr)
+	return ok && nil != o && k.val == o.val
+}
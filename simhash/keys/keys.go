@@ -0,0 +1,36 @@
+/*
+   Copyright 2017 Joseph Cumines
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+
+ */
+
+// Package keys provides ready-made implementations of simhash.Key, for primitives, strings, bytes, times, and tuples
+// of other keys. Every key implements simhash.Key64, with a well distributed hash, and fmt.Stringer, which for all
+// keys except TupleKey returns a representation that can be parsed back into an equal key, so that the output of
+// simhash.Map.Serialize round-trips.
+// Keys of different types are never equal, e.g. IntKey(1) does not equal Int64Key(1), the same as in Java.
+// All keys except TupleKey also implement simhash.Comparable.
+package keys
+
+// compareOrder returns -1 if less, 1 if greater, or otherwise 0.
+func compareOrder(less, greater bool) int {
+	switch {
+	case less:
+		return -1
+	case greater:
+		return 1
+	default:
+		return 0
+	}
+}
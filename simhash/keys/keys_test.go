@@ -0,0 +1,128 @@
+/*
+   Copyright 2017 Joseph Cumines
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+
+ */
+
+package keys
+
+import (
+	"math"
+	"math/rand"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/joeycumines/go-hashmap/simhash"
+	"github.com/joeycumines/go-hashmap/simhash/hashing"
+	"github.com/joeycumines/go-hashmap/simhash/simhashtest"
+)
+
+var (
+	_ simhash.Key64      = IntKey(0)
+	_ simhash.Key64      = Int64Key(0)
+	_ simhash.Key64      = Uint64Key(0)
+	_ simhash.Key64      = Float64Key(0)
+	_ simhash.Key64      = StringKey("")
+	_ simhash.Key64      = BytesKey(nil)
+	_ simhash.Key64      = TimeKey{}
+	_ simhash.Key64      = TupleKey(nil)
+	_ simhash.Comparable = IntKey(0)
+	_ simhash.Comparable = Int64Key(0)
+	_ simhash.Comparable = Uint64Key(0)
+	_ simhash.Comparable = Float64Key(0)
+	_ simhash.Comparable = StringKey("")
+	_ simhash.Comparable = BytesKey(nil)
+	_ simhash.Comparable = TimeKey{}
+)
+
+// testKeys contains one key of every type, none of which are equal.
+func testKeys() []simhash.Key {
+	return []simhash.Key{
+		IntKey(1),
+		Int64Key(1),
+		Uint64Key(1),
+		Float64Key(1),
+		StringKey("1"),
+		Bytes([]byte("1")),
+		TimeKey{time.Unix(1, 0)},
+		Tuple(IntKey(1)),
+	}
+}
+
+// TestHashString guards against accidental changes, as the hashes of strings are the mixed FNV-1a hash.
+func TestHashString(t *testing.T) {
+	if hashing.Mix(hashing.FNV64a("")) != StringKey("").Hash64() || hashing.Mix(hashing.FNV64a("abc")) != Bytes([]byte("abc")).Hash64() {
+		t.Fatal()
+	}
+}
+
+func TestKeys_distinctTypes(t *testing.T) {
+	keys := testKeys()
+	for i, a := range keys {
+		if false == a.Equals(a) || a.Hash() != hashing.Fold(a.(simhash.Key64).Hash64()) {
+			t.Fatal(a)
+		}
+		for j, b := range keys {
+			if i != j && true == a.Equals(b) {
+				t.Fatal(a, b)
+			}
+		}
+		if true == a.Equals(nil) {
+			t.Fatal(a)
+		}
+	}
+}
+
+func TestKeys_Serialize(t *testing.T) {
+	m := simhash.NewMap()
+	for i := 0; i < 10; i++ {
+		m.Put(IntKey(i), i)
+	}
+	s := m.Serialize()
+	if 10 != len(s) {
+		t.Fatalf("unexpected: %v", s)
+	}
+	for k, v := range s {
+		i, err := strconv.Atoi(k)
+		if nil != err || i != v.(int) || true != m.Contains(IntKey(i)) {
+			t.Fatal(k, v)
+		}
+	}
+}
+
+func TestKeys_contract(t *testing.T) {
+	floats := []float64{0, math.Copysign(0, -1), math.NaN(), math.Inf(1), 1.5}
+	simhashtest.CheckKeyContract(
+		t,
+		func(r *rand.Rand, size int) simhash.Key { return IntKey(r.Intn(size+1) - size/2) },
+		func(r *rand.Rand, size int) simhash.Key { return Int64Key(r.Int63n(int64(size) + 1)) },
+		func(r *rand.Rand, size int) simhash.Key { return Uint64Key(r.Int63n(int64(size) + 1)) },
+		func(r *rand.Rand, size int) simhash.Key { return Float64Key(floats[r.Intn(len(floats))]) },
+		func(r *rand.Rand, size int) simhash.Key { return StringKey(strconv.Itoa(r.Intn(size + 1))) },
+		func(r *rand.Rand, size int) simhash.Key { return Bytes([]byte(strconv.Itoa(r.Intn(size + 1)))) },
+		func(r *rand.Rand, size int) simhash.Key {
+			return TimeKey{time.Unix(int64(r.Intn(size+1)), 0).In(time.FixedZone("", 60*r.Intn(2)))}
+		},
+		func(r *rand.Rand, size int) simhash.Key {
+			tuple := make(TupleKey, r.Intn(3))
+			for i := range tuple {
+				if 0 != r.Intn(4) {
+					tuple[i] = IntKey(r.Intn(size + 1))
+				}
+			}
+			return tuple
+		},
+	)
+}
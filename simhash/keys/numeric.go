@@ -0,0 +1,150 @@
+/*
+   Copyright 2017 Joseph Cumines
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+
+ */
+
+package keys
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/joeycumines/go-hashmap/simhash/hashing"
+)
+
+// IntKey is a simhash.Key for an int.
+type IntKey int
+
+// Int64Key is a simhash.Key for an int64.
+type Int64Key int64
+
+// Uint64Key is a simhash.Key for a uint64.
+type Uint64Key uint64
+
+// Float64Key is a simhash.Key for a float64, which follows the semantics of Java's Double.equals, rather than ==,
+// that is all NaN values are equal to each other, and 0 and -0 are not equal. Compare follows Java's Double.compare,
+// where -0 is less than 0, and NaN is greater than every other value, including positive infinity.
+type Float64Key float64
+
+func (k IntKey) Hash() int {
+	return hashing.Fold(k.Hash64())
+}
+
+func (k IntKey) Hash64() uint64 {
+	return hashing.Mix(uint64(k))
+}
+
+func (k IntKey) Equals(other interface{}) bool {
+	o, ok := other.(IntKey)
+	return ok && k == o
+}
+
+func (k IntKey) Compare(other interface{}) int {
+	o := other.(IntKey)
+	return compareOrder(k < o, k > o)
+}
+
+// String returns the key in base 10, which may be parsed using strconv.Atoi.
+func (k IntKey) String() string {
+	return strconv.Itoa(int(k))
+}
+
+func (k Int64Key) Hash() int {
+	return hashing.Fold(k.Hash64())
+}
+
+func (k Int64Key) Hash64() uint64 {
+	return hashing.Mix(uint64(k))
+}
+
+func (k Int64Key) Equals(other interface{}) bool {
+	o, ok := other.(Int64Key)
+	return ok && k == o
+}
+
+func (k Int64Key) Compare(other interface{}) int {
+	o := other.(Int64Key)
+	return compareOrder(k < o, k > o)
+}
+
+// String returns the key in base 10, which may be parsed using strconv.ParseInt.
+func (k Int64Key) String() string {
+	return strconv.FormatInt(int64(k), 10)
+}
+
+func (k Uint64Key) Hash() int {
+	return hashing.Fold(k.Hash64())
+}
+
+func (k Uint64Key) Hash64() uint64 {
+	return hashing.Mix(uint64(k))
+}
+
+func (k Uint64Key) Equals(other interface{}) bool {
+	o, ok := other.(Uint64Key)
+	return ok && k == o
+}
+
+func (k Uint64Key) Compare(other interface{}) int {
+	o := other.(Uint64Key)
+	return compareOrder(k < o, k > o)
+}
+
+// String returns the key in base 10, which may be parsed using strconv.ParseUint.
+func (k Uint64Key) String() string {
+	return strconv.FormatUint(uint64(k), 10)
+}
+
+// bits returns the IEEE 754 representation of k, where every NaN has the same representation, the same as Java's
+// Double.doubleToLongBits.
+func (k Float64Key) bits() uint64 {
+	if f := float64(k); f != f {
+		return 0x7ff8000000000000
+	}
+	return math.Float64bits(float64(k))
+}
+
+func (k Float64Key) Hash() int {
+	return hashing.Fold(k.Hash64())
+}
+
+func (k Float64Key) Hash64() uint64 {
+	return hashing.Mix(k.bits())
+}
+
+func (k Float64Key) Equals(other interface{}) bool {
+	o, ok := other.(Float64Key)
+	return ok && k.bits() == o.bits()
+}
+
+func (k Float64Key) Compare(other interface{}) int {
+	o := other.(Float64Key)
+	if k < o {
+		return -1
+	}
+	if k > o {
+		return 1
+	}
+	// equal (including 0 and -0), or at least one is NaN, which the bits order correctly, as signed integers, given
+	// that NaN is canonicalised to a positive value
+	a, b := int64(k.bits()), int64(o.bits())
+	return compareOrder(a < b, a > b)
+}
+
+// String returns the shortest representation of the key that may be parsed using strconv.ParseFloat, including
+// "NaN", "+Inf", "-Inf" and "-0".
+func (k Float64Key) String() string {
+	return strconv.FormatFloat(float64(k), 'g', -1, 64)
+}
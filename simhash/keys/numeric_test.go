@@ -0,0 +1,137 @@
+/*
+   Copyright 2017 Joseph Cumines
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+
+ */
+
+package keys
+
+import (
+	"math"
+	"strconv"
+	"testing"
+)
+
+func TestIntKey(t *testing.T) {
+	if IntKey(1).Hash() == IntKey(2).Hash() || IntKey(1).Hash64() != Int64Key(1).Hash64() {
+		t.Fatal()
+	}
+	if true != IntKey(-3).Equals(IntKey(-3)) || true == IntKey(-3).Equals(IntKey(3)) {
+		t.Fatal()
+	}
+	if -1 != IntKey(-3).Compare(IntKey(3)) || 1 != IntKey(3).Compare(IntKey(-3)) || 0 != IntKey(3).Compare(IntKey(3)) {
+		t.Fatal()
+	}
+	if i, err := strconv.Atoi(IntKey(-42).String()); nil != err || -42 != i {
+		t.Fatal(i, err)
+	}
+}
+
+func TestInt64Key(t *testing.T) {
+	if Int64Key(math.MinInt64).Hash64() == Int64Key(math.MaxInt64).Hash64() {
+		t.Fatal()
+	}
+	if true != Int64Key(-3).Equals(Int64Key(-3)) || true == Int64Key(-3).Equals(IntKey(-3)) {
+		t.Fatal()
+	}
+	if -1 != Int64Key(math.MinInt64).Compare(Int64Key(math.MaxInt64)) || 0 != Int64Key(5).Compare(Int64Key(5)) {
+		t.Fatal()
+	}
+	if i, err := strconv.ParseInt(Int64Key(math.MinInt64).String(), 10, 64); nil != err || math.MinInt64 != i {
+		t.Fatal(i, err)
+	}
+}
+
+func TestUint64Key(t *testing.T) {
+	if Uint64Key(0).Hash64() == Uint64Key(math.MaxUint64).Hash64() {
+		t.Fatal()
+	}
+	if true != Uint64Key(3).Equals(Uint64Key(3)) || true == Uint64Key(3).Equals(Int64Key(3)) {
+		t.Fatal()
+	}
+	if -1 != Uint64Key(0).Compare(Uint64Key(math.MaxUint64)) || 1 != Uint64Key(math.MaxUint64).Compare(Uint64Key(0)) {
+		t.Fatal()
+	}
+	if i, err := strconv.ParseUint(Uint64Key(math.MaxUint64).String(), 10, 64); nil != err || math.MaxUint64 != i {
+		t.Fatal(i, err)
+	}
+}
+
+func TestFloat64Key_Equals(t *testing.T) {
+	nan := Float64Key(math.NaN())
+	otherNaN := Float64Key(math.Float64frombits(0xfff0000000000001))
+	if otherNaN == otherNaN {
+		t.Fatal("expected a NaN")
+	}
+	if true != nan.Equals(nan) || true != nan.Equals(otherNaN) || nan.Hash64() != otherNaN.Hash64() {
+		t.Fatal()
+	}
+	zero, negZero := Float64Key(0), Float64Key(math.Copysign(0, -1))
+	if true == zero.Equals(negZero) || zero.Hash64() == negZero.Hash64() {
+		t.Fatal()
+	}
+	if true != Float64Key(1.5).Equals(Float64Key(1.5)) || true == Float64Key(1.5).Equals(nan) {
+		t.Fatal()
+	}
+}
+
+func TestFloat64Key_Compare(t *testing.T) {
+	nan := Float64Key(math.NaN())
+	ordered := []Float64Key{
+		Float64Key(math.Inf(-1)),
+		-1,
+		Float64Key(math.Copysign(0, -1)),
+		0,
+		Float64Key(math.SmallestNonzeroFloat64),
+		1,
+		Float64Key(math.Inf(1)),
+		nan,
+	}
+	for i, a := range ordered {
+		for j, b := range ordered {
+			expected := compareOrder(i < j, i > j)
+			if actual := a.Compare(b); expected != actual {
+				t.Error(a, b, expected, actual)
+			}
+			if (0 == expected) != a.Equals(b) {
+				t.Error(a, b)
+			}
+		}
+	}
+	if 0 != nan.Compare(Float64Key(math.Float64frombits(0xfff0000000000001))) {
+		t.Fatal()
+	}
+}
+
+func TestFloat64Key_String(t *testing.T) {
+	for _, k := range []Float64Key{
+		0,
+		Float64Key(math.Copysign(0, -1)),
+		0.1,
+		-1e300,
+		Float64Key(math.Inf(1)),
+		Float64Key(math.Inf(-1)),
+		Float64Key(math.NaN()),
+		math.MaxFloat64,
+		math.SmallestNonzeroFloat64,
+	} {
+		f, err := strconv.ParseFloat(k.String(), 64)
+		if nil != err || false == k.Equals(Float64Key(f)) {
+			t.Error(k, k.String(), f, err)
+		}
+	}
+	if "-0" != Float64Key(math.Copysign(0, -1)).String() || "NaN" != Float64Key(math.NaN()).String() {
+		t.Fatal()
+	}
+}
@@ -0,0 +1,83 @@
+/*
+   Copyright 2017 Joseph Cumines
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+
+ */
+
+package keys
+
+import (
+	"bytes"
+	"encoding/hex"
+
+	"github.com/joeycumines/go-hashmap/simhash/hashing"
+)
+
+// StringKey is a simhash.Key for a string.
+type StringKey string
+
+// BytesKey is a simhash.Key for a byte slice, which compares the contents of the slice, and must therefore not be
+// modified while it is stored in a map, see Bytes.
+type BytesKey []byte
+
+func (k StringKey) Hash() int {
+	return hashing.Fold(k.Hash64())
+}
+
+func (k StringKey) Hash64() uint64 {
+	return hashing.Mix(hashing.FNV64a(string(k)))
+}
+
+func (k StringKey) Equals(other interface{}) bool {
+	o, ok := other.(StringKey)
+	return ok && k == o
+}
+
+func (k StringKey) Compare(other interface{}) int {
+	o := other.(StringKey)
+	return compareOrder(k < o, k > o)
+}
+
+// String returns the string itself.
+func (k StringKey) String() string {
+	return string(k)
+}
+
+// Bytes returns a BytesKey containing a copy of b, which may therefore be safely modified.
+func Bytes(b []byte) BytesKey {
+	return append(BytesKey{}, b...)
+}
+
+func (k BytesKey) Hash() int {
+	return hashing.Fold(k.Hash64())
+}
+
+func (k BytesKey) Hash64() uint64 {
+	return hashing.Mix(hashing.FNV64aBytes(k))
+}
+
+// Equals will return true if other is a BytesKey with the same contents, where a nil slice equals an empty one.
+func (k BytesKey) Equals(other interface{}) bool {
+	o, ok := other.(BytesKey)
+	return ok && bytes.Equal(k, o)
+}
+
+func (k BytesKey) Compare(other interface{}) int {
+	return bytes.Compare(k, other.(BytesKey))
+}
+
+// String returns the bytes encoded as hexadecimal, which may be parsed using hex.DecodeString.
+func (k BytesKey) String() string {
+	return hex.EncodeToString(k)
+}
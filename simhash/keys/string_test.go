@@ -0,0 +1,70 @@
+/*
+   Copyright 2017 Joseph Cumines
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+
+ */
+
+package keys
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+func TestStringKey(t *testing.T) {
+	if StringKey("a").Hash64() == StringKey("b").Hash64() || StringKey("").Hash64() == StringKey("\x00").Hash64() {
+		t.Fatal()
+	}
+	if true != StringKey("a").Equals(StringKey("a")) || true == StringKey("a").Equals(Bytes([]byte("a"))) {
+		t.Fatal()
+	}
+	if -1 != StringKey("a").Compare(StringKey("b")) || 1 != StringKey("b").Compare(StringKey("a")) ||
+		0 != StringKey("a").Compare(StringKey("a")) {
+		t.Fatal()
+	}
+	if "a b" != StringKey("a b").String() {
+		t.Fatal()
+	}
+}
+
+func TestBytes(t *testing.T) {
+	b := []byte("abc")
+	k := Bytes(b)
+	b[0] = 'x'
+	if "616263" != k.String() {
+		t.Fatal(k.String())
+	}
+	if nil == Bytes(nil) || 0 != len(Bytes(nil)) {
+		t.Fatal()
+	}
+}
+
+func TestBytesKey(t *testing.T) {
+	if true != BytesKey(nil).Equals(BytesKey{}) || BytesKey(nil).Hash64() != (BytesKey{}).Hash64() {
+		t.Fatal()
+	}
+	if true != Bytes([]byte("ab")).Equals(Bytes([]byte("ab"))) || true == Bytes([]byte("ab")).Equals(Bytes([]byte("a"))) {
+		t.Fatal()
+	}
+	if Bytes([]byte("ab")).Hash64() != StringKey("ab").Hash64() || Bytes([]byte("ab")).Hash64() == Bytes([]byte("ba")).Hash64() {
+		t.Fatal()
+	}
+	if -1 != Bytes([]byte("a")).Compare(Bytes([]byte("ab"))) || 0 != BytesKey(nil).Compare(BytesKey{}) {
+		t.Fatal()
+	}
+	k := BytesKey{0, 1, 0xff}
+	if b, err := hex.DecodeString(k.String()); nil != err || false == k.Equals(BytesKey(b)) {
+		t.Fatal(b, err)
+	}
+}
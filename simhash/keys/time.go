@@ -0,0 +1,54 @@
+/*
+   Copyright 2017 Joseph Cumines
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+
+ */
+
+package keys
+
+import (
+	"time"
+
+	"github.com/joeycumines/go-hashmap/simhash/hashing"
+)
+
+// TimeKey is a simhash.Key for a time.Time, which is equal to any other TimeKey for the same instant, regardless of
+// location or monotonic clock reading, the same as time.Time.Equal.
+type TimeKey struct {
+	time.Time
+}
+
+func (k TimeKey) Hash() int {
+	return hashing.Fold(k.Hash64())
+}
+
+func (k TimeKey) Hash64() uint64 {
+	return hashing.Mix(uint64(k.Unix())*1000000000 + uint64(k.Nanosecond()))
+}
+
+func (k TimeKey) Equals(other interface{}) bool {
+	o, ok := other.(TimeKey)
+	return ok && k.Time.Equal(o.Time)
+}
+
+func (k TimeKey) Compare(other interface{}) int {
+	o := other.(TimeKey)
+	return compareOrder(k.Before(o.Time), k.After(o.Time))
+}
+
+// String returns the time in RFC 3339 format, with nanoseconds, which may be parsed using time.Parse with
+// time.RFC3339Nano.
+func (k TimeKey) String() string {
+	return k.Format(time.RFC3339Nano)
+}
@@ -0,0 +1,51 @@
+/*
+   Copyright 2017 Joseph Cumines
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+
+ */
+
+package keys
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimeKey(t *testing.T) {
+	utc := time.Date(2017, 10, 1, 12, 30, 0, 123456789, time.UTC)
+	local := utc.In(time.FixedZone("test", 10*60*60))
+	if true != (TimeKey{utc}).Equals(TimeKey{local}) || (TimeKey{utc}).Hash64() != (TimeKey{local}).Hash64() {
+		t.Fatal()
+	}
+	// monotonic clock readings are ignored
+	now := time.Now()
+	if true != (TimeKey{now}).Equals(TimeKey{now.Round(0)}) || (TimeKey{now}).Hash() != (TimeKey{now.Round(0)}).Hash() {
+		t.Fatal()
+	}
+	later := utc.Add(time.Nanosecond)
+	if true == (TimeKey{utc}).Equals(TimeKey{later}) || (TimeKey{utc}).Hash64() == (TimeKey{later}).Hash64() ||
+		true == (TimeKey{utc}).Equals(utc) {
+		t.Fatal()
+	}
+	if -1 != (TimeKey{utc}).Compare(TimeKey{later}) || 1 != (TimeKey{later}).Compare(TimeKey{utc}) ||
+		0 != (TimeKey{utc}).Compare(TimeKey{local}) {
+		t.Fatal()
+	}
+	if "2017-10-01T22:30:00.123456789+10:00" != (TimeKey{local}).String() {
+		t.Fatal((TimeKey{local}).String())
+	}
+	if p, err := time.Parse(time.RFC3339Nano, (TimeKey{local}).String()); nil != err || false == (TimeKey{utc}).Equals(TimeKey{p}) {
+		t.Fatal(p, err)
+	}
+}
@@ -0,0 +1,88 @@
+/*
+   Copyright 2017 Joseph Cumines
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+
+ */
+
+package keys
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/joeycumines/go-hashmap/simhash"
+	"github.com/joeycumines/go-hashmap/simhash/hashing"
+)
+
+// TupleKey is a simhash.Key combining several keys, any of which may be nil, which is equal to another TupleKey of
+// the same length, where each key is equal to the key in the same position. It must not be modified while it is
+// stored in a map, see Tuple.
+type TupleKey []simhash.Key
+
+// Tuple returns a TupleKey containing a copy of keys.
+func Tuple(keys ...simhash.Key) TupleKey {
+	return append(TupleKey{}, keys...)
+}
+
+func (k TupleKey) Hash() int {
+	return hashing.Fold(k.Hash64())
+}
+
+// Hash64 combines the 64 bit hashes of each key, taking their position into account, see hashing.Hasher.Key, which
+// gives nil keys a hash distinct from zero numeric keys.
+func (k TupleKey) Hash64() uint64 {
+	h := hashing.NewHasher().Int(len(k))
+	for _, key := range k {
+		h.Key(key)
+	}
+	return h.Hash64()
+}
+
+func (k TupleKey) Equals(other interface{}) bool {
+	o, ok := other.(TupleKey)
+	if false == ok || len(k) != len(o) {
+		return false
+	}
+	for i, key := range k {
+		if nil == key || nil == o[i] {
+			if key != o[i] {
+				return false
+			}
+			continue
+		}
+		if false == key.Equals(o[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// String formats the tuple as it's keys, separated by commas, and wrapped in parentheses, using fmt.Stringer where
+// it is implemented, e.g. "(1, a, <nil>)". Unlike the other keys, it cannot be parsed, as the types of the keys are
+// not retained.
+func (k TupleKey) String() string {
+	b := bytes.NewBufferString("(")
+	for i, key := range k {
+		if 0 != i {
+			b.WriteString(", ")
+		}
+		if s, ok := key.(fmt.Stringer); true == ok {
+			b.WriteString(s.String())
+			continue
+		}
+		fmt.Fprintf(b, "%v", key)
+	}
+	b.WriteString(")")
+	return b.String()
+}
@@ -0,0 +1,97 @@
+/*
+   Copyright 2017 Joseph Cumines
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+
+ */
+
+package keys
+
+import (
+	"testing"
+
+	"github.com/joeycumines/go-hashmap/simhash"
+	"github.com/joeycumines/go-hashmap/simhash/hashing"
+)
+
+func TestTuple(t *testing.T) {
+	keys := []simhash.Key{IntKey(1), StringKey("a")}
+	k := Tuple(keys...)
+	keys[0] = IntKey(2)
+	if false == k.Equals(TupleKey{IntKey(1), StringKey("a")}) {
+		t.Fatal(k)
+	}
+	if nil == Tuple() || 0 != len(Tuple()) {
+		t.Fatal()
+	}
+}
+
+func TestTupleKey_Equals(t *testing.T) {
+	a := Tuple(IntKey(1), nil, StringKey("a"))
+	if true != a.Equals(Tuple(IntKey(1), nil, StringKey("a"))) || a.Hash64() != Tuple(IntKey(1), nil, StringKey("a")).Hash64() {
+		t.Fatal()
+	}
+	for _, b := range []simhash.Key{
+		Tuple(IntKey(1), nil),
+		Tuple(IntKey(1), nil, StringKey("a"), nil),
+		Tuple(IntKey(1), StringKey("a"), nil),
+		Tuple(IntKey(1), IntKey(0), StringKey("a")),
+		IntKey(1),
+		nil,
+	} {
+		if true == a.Equals(b) {
+			t.Fatal(b)
+		}
+		if b, ok := b.(TupleKey); true == ok && a.Hash64() == b.Hash64() {
+			t.Fatal(b)
+		}
+	}
+	// equal numeric values of different types have the same hash, but are not equal
+	if true == a.Equals(Tuple(Int64Key(1), nil, StringKey("a"))) {
+		t.Fatal()
+	}
+	if true != Tuple().Equals(TupleKey(nil)) || Tuple().Hash() != TupleKey(nil).Hash() {
+		t.Fatal()
+	}
+	// position matters
+	if Tuple(IntKey(1), IntKey(2)).Hash64() == Tuple(IntKey(2), IntKey(1)).Hash64() {
+		t.Fatal()
+	}
+	// the hash is the same as combining the length and keys using a hasher
+	if a.Hash64() != hashing.NewHasher().Int(3).Key(IntKey(1)).Key(nil).Key(StringKey("a")).Hash64() {
+		t.Fatal()
+	}
+	// nested
+	if true != Tuple(Tuple(IntKey(1)), nil).Equals(Tuple(Tuple(IntKey(1)), nil)) {
+		t.Fatal()
+	}
+}
+
+func TestTupleKey_String(t *testing.T) {
+	if s := Tuple(IntKey(1), StringKey("a"), nil, Tuple(Float64Key(0.5))).String(); "(1, a, <nil>, (0.5))" != s {
+		t.Fatal(s)
+	}
+	if "()" != Tuple().String() {
+		t.Fatal()
+	}
+}
+
+func TestTupleKey_map(t *testing.T) {
+	m := simhash.NewMap()
+	for i := 0; i < 100; i++ {
+		m.Put(Tuple(IntKey(i/10), IntKey(i%10)), i)
+	}
+	if 100 != m.Size() || 42 != m.Get(Tuple(IntKey(4), IntKey(2))).(int) || nil != m.Get(Tuple(IntKey(4))) {
+		t.Fatal(m.Size())
+	}
+}
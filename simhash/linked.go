@@ -0,0 +1,285 @@
+/*
+   Copyright 2017 Joseph Cumines
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+
+ */
+
+package simhash
+
+import (
+	"fmt"
+)
+
+// linkedEntry is the Pair stored in the buckets of a linkedMap, which also forms a node in a doubly linked list.
+// Entries are mutable, and are therefore never returned to callers directly.
+type linkedEntry struct {
+	key   Key
+	value Value
+	prev  *linkedEntry
+	next  *linkedEntry
+}
+
+func (e *linkedEntry) Key() Key {
+	return e.key
+}
+
+func (e *linkedEntry) Value() Value {
+	return e.value
+}
+
+// linkedMap is a Map that remembers the order of it's keys, the same as Java's LinkedHashMap, by storing entries in
+// a hashMap, which are also linked together, in either insertion order, or access order (least recently accessed
+// first). Re-inserting an existing key will not change it's position in insertion order.
+// It has the same concurrency restrictions as hashMap.
+type linkedMap struct {
+	m           *hashMap
+	root        linkedEntry
+	accessOrder bool
+	// modCount is incremented whenever the list is changed, including by access.
+	modCount int
+}
+
+func (m *linkedMap) entry(h, i int) *linkedEntry {
+	return m.m.m[h][i].(*linkedEntry)
+}
+
+// link adds e to the end of the list.
+func (m *linkedMap) link(e *linkedEntry) {
+	e.prev = m.root.prev
+	e.next = &m.root
+	e.prev.next = e
+	m.root.prev = e
+	m.modCount++
+}
+
+func (m *linkedMap) unlink(e *linkedEntry) {
+	e.prev.next = e.next
+	e.next.prev = e.prev
+	e.prev = nil
+	e.next = nil
+	m.modCount++
+}
+
+// access moves e to the end of the list, if the map is in access order.
+func (m *linkedMap) access(e *linkedEntry) {
+	if true == m.accessOrder && m.root.prev != e {
+		m.unlink(e)
+		m.link(e)
+	}
+}
+
+func (m *linkedMap) add(h int, key Key, value Value) {
+	e := &linkedEntry{key: key, value: value}
+	m.m.insert(h, e)
+	m.link(e)
+}
+
+func (m *linkedMap) removeAt(h, i int) Value {
+	e := m.entry(h, i)
+	m.m.removeAt(h, i)
+	m.unlink(e)
+	return e.value
+}
+
+func (m *linkedMap) Contains(key Key) bool {
+	return m.m.Contains(key)
+}
+
+func (m *linkedMap) Get(key Key) Value {
+	h, i, ok := m.m.lookup(key)
+	if false == ok {
+		return nil
+	}
+	e := m.entry(h, i)
+	m.access(e)
+	return e.value
+}
+
+func (m *linkedMap) Put(key Key, value Value) Value {
+	h := m.m.hash(key)
+	if i, ok := m.m.find(h, key); true == ok {
+		e := m.entry(h, i)
+		v := e.value
+		e.value = value
+		m.access(e)
+		return v
+	}
+	m.add(h, key, value)
+	return nil
+}
+
+func (m *linkedMap) Remove(key Key) Value {
+	h, i, ok := m.m.lookup(key)
+	if false == ok {
+		return nil
+	}
+	return m.removeAt(h, i)
+}
+
+func (m *linkedMap) ComputeIfAbsent(key Key, fn func(key Key) Value) Value {
+	h := m.m.hash(key)
+	if i, ok := m.m.find(h, key); true == ok {
+		e := m.entry(h, i)
+		m.access(e)
+		return e.value
+	}
+	value := fn(key)
+	if nil != value {
+		m.add(h, key, value)
+	}
+	return value
+}
+
+func (m *linkedMap) ComputeIfPresent(key Key, fn func(key Key, value Value) Value) Value {
+	h := m.m.hash(key)
+	i, ok := m.m.find(h, key)
+	if false == ok {
+		return nil
+	}
+	e := m.entry(h, i)
+	value := fn(key, e.value)
+	if nil == value {
+		m.removeAt(h, i)
+		return nil
+	}
+	e.value = value
+	m.access(e)
+	return value
+}
+
+func (m *linkedMap) Compute(key Key, fn func(key Key, value Value) Value) Value {
+	h := m.m.hash(key)
+	i, ok := m.m.find(h, key)
+	var e *linkedEntry
+	var old Value
+	if true == ok {
+		e = m.entry(h, i)
+		old = e.value
+	}
+	value := fn(key, old)
+	switch {
+	case nil == value && true == ok:
+		m.removeAt(h, i)
+	case nil == value:
+	case true == ok:
+		e.value = value
+		m.access(e)
+	default:
+		m.add(h, key, value)
+	}
+	return value
+}
+
+func (m *linkedMap) Merge(key Key, value Value, fn func(old, new Value) Value) Value {
+	h := m.m.hash(key)
+	i, ok := m.m.find(h, key)
+	if false == ok {
+		if nil != value {
+			m.add(h, key, value)
+		}
+		return value
+	}
+	e := m.entry(h, i)
+	value = fn(e.value, value)
+	if nil == value {
+		m.removeAt(h, i)
+		return nil
+	}
+	e.value = value
+	m.access(e)
+	return value
+}
+
+func (m *linkedMap) Keys() []Key {
+	keys := make([]Key, 0, m.m.size)
+	for e := m.root.next; &m.root != e; e = e.next {
+		keys = append(keys, e.key)
+	}
+	return keys
+}
+
+func (m *linkedMap) Values() []Value {
+	values := make([]Value, 0, m.m.size)
+	for e := m.root.next; &m.root != e; e = e.next {
+		values = append(values, e.value)
+	}
+	return values
+}
+
+func (m *linkedMap) Pairs() []Pair {
+	pairs := make([]Pair, 0, m.m.size)
+	for e := m.root.next; &m.root != e; e = e.next {
+		pairs = append(pairs, NewPair(e.key, e.value))
+	}
+	return pairs
+}
+
+func (m *linkedMap) Size() int {
+	return m.m.size
+}
+
+func (m *linkedMap) Serialize() map[string]interface{} {
+	serialized := make(map[string]interface{})
+	for e := m.root.next; &m.root != e; e = e.next {
+		kStringer, ok := e.key.(fmt.Stringer)
+		var k string
+		if true == ok {
+			k = kStringer.String()
+		} else {
+			k = fmt.Sprintf("%v", e.key)
+		}
+		serialized[k] = e.value
+	}
+	return serialized
+}
+
+// Iterator returns an iterator over a snapshot of the pairs in the map, in order, which will not affect the access
+// order, including SetValue, which will also not add the key back, if it has since been removed from the map.
+func (m *linkedMap) Iterator() Iterator {
+	it := newSnapshotIterator(m, m.Pairs())
+	it.setValue = func(key Key, value Value) Value {
+		h, i, ok := m.m.lookup(key)
+		if false == ok {
+			return nil
+		}
+		e := m.entry(h, i)
+		v := e.value
+		e.value = value
+		return v
+	}
+	return it
+}
+
+func newLinkedMap(accessOrder bool) *linkedMap {
+	m := &linkedMap{
+		m:           NewMap().(*hashMap),
+		accessOrder: accessOrder,
+	}
+	m.root.prev = &m.root
+	m.root.next = &m.root
+	return m
+}
+
+// NewLinkedMap creates a new Map which remembers the order in which keys were inserted, which will be used for
+// Keys, Values, Pairs, and Iterator. The returned map also implements ComputeMap. It is not safe for concurrent use.
+func NewLinkedMap() Map {
+	return newLinkedMap(false)
+}
+
+// NewAccessOrderedLinkedMap creates a new Map which orders it's keys from least to most recently accessed, where
+// Get, Put, and the ComputeMap operations all count as an access of the key, but Contains and iteration do not.
+// It is otherwise the same as NewLinkedMap.
+func NewAccessOrderedLinkedMap() Map {
+	return newLinkedMap(true)
+}
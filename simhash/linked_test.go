@@ -0,0 +1,143 @@
+/*
+   Copyright 2017 Joseph Cumines
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+
+ */
+
+package simhash
+
+import (
+	"testing"
+)
+
+func linkedMapValues(m Map) []int {
+	values := make([]int, 0)
+	for _, v := range m.Values() {
+		values = append(values, v.(int))
+	}
+	return values
+}
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestNewLinkedMap(t *testing.T) {
+	m := NewLinkedMap().(*linkedMap)
+	if nil == m.m || 0 != m.Size() || &m.root != m.root.next || &m.root != m.root.prev || false != m.accessOrder {
+		t.Fatal()
+	}
+	if true != NewAccessOrderedLinkedMap().(*linkedMap).accessOrder {
+		t.Fatal()
+	}
+	var _ ComputeMap = m
+}
+
+func TestLinkedMap_insertionOrder(t *testing.T) {
+	m := NewLinkedMap()
+	for _, i := range []int{5, 3, 9, 1, 7} {
+		m.Put(testKeyStruct{i % 2, i}, i)
+	}
+	m.Put(nil, 0)
+	if false == equalInts([]int{5, 3, 9, 1, 7, 0}, linkedMapValues(m)) {
+		t.Fatal(linkedMapValues(m))
+	}
+	// updating doesn't change the order, and neither does get
+	if 9 != m.Put(testKeyStruct{1, 9}, 9).(int) || 5 != m.Get(testKeyStruct{1, 5}).(int) {
+		t.Fatal()
+	}
+	if false == equalInts([]int{5, 3, 9, 1, 7, 0}, linkedMapValues(m)) {
+		t.Fatal(linkedMapValues(m))
+	}
+	if 3 != m.Remove(testKeyStruct{1, 3}).(int) || 0 != m.Remove(nil).(int) || nil != m.Remove(testKeyInt(3)) || 4 != m.Size() {
+		t.Fatal()
+	}
+	m.Put(testKeyStruct{1, 3}, 3)
+	if false == equalInts([]int{5, 9, 1, 7, 3}, linkedMapValues(m)) {
+		t.Fatal(linkedMapValues(m))
+	}
+	keys := m.Keys()
+	pairs := m.Pairs()
+	if 5 != len(keys) || 5 != len(pairs) {
+		t.Fatal()
+	}
+	for i, v := range []int{5, 9, 1, 7, 3} {
+		if v != keys[i].(testKeyStruct).val || v != pairs[i].Key().(testKeyStruct).val || v != pairs[i].Value().(int) {
+			t.Fatal()
+		}
+	}
+	// returned pairs are not affected by later updates
+	m.Put(testKeyStruct{1, 5}, 55)
+	if 5 != pairs[0].Value().(int) || 55 != m.Get(testKeyStruct{1, 5}).(int) {
+		t.Fatal()
+	}
+	if s := m.Serialize(); 5 != len(s) || 55 != s["5"].(int) || 3 != s["3"].(int) {
+		t.Fatal(s)
+	}
+}
+
+func TestLinkedMap_accessOrder(t *testing.T) {
+	m := NewAccessOrderedLinkedMap()
+	for i := 1; i <= 5; i++ {
+		m.Put(testKeyInt(i), i)
+	}
+	m.Get(testKeyInt(2))
+	m.Put(testKeyInt(1), 1)
+	m.Contains(testKeyInt(3))
+	m.Get(testKeyInt(5))
+	if false == equalInts([]int{3, 4, 2, 1, 5}, linkedMapValues(m)) {
+		t.Fatal(linkedMapValues(m))
+	}
+	cm := m.(ComputeMap)
+	cm.ComputeIfAbsent(testKeyInt(3), func(key Key) Value { return nil })
+	cm.Merge(testKeyInt(4), 10, func(old, new Value) Value { return old.(int) + new.(int) })
+	if false == equalInts([]int{2, 1, 5, 3, 14}, linkedMapValues(m)) {
+		t.Fatal(linkedMapValues(m))
+	}
+	cm.Compute(testKeyInt(2), func(key Key, value Value) Value { return nil })
+	cm.ComputeIfPresent(testKeyInt(1), func(key Key, value Value) Value { return 11 })
+	cm.Compute(testKeyInt(6), func(key Key, value Value) Value { return 6 })
+	if false == equalInts([]int{5, 3, 14, 11, 6}, linkedMapValues(m)) {
+		t.Fatal(linkedMapValues(m))
+	}
+}
+
+func TestLinkedMap_Iterator(t *testing.T) {
+	m := NewAccessOrderedLinkedMap()
+	for i := 1; i <= 4; i++ {
+		m.Put(testKeyInt(i), i)
+	}
+	it := m.Iterator()
+	values := make([]int, 0)
+	for true == it.Next() {
+		values = append(values, it.Value().(int))
+	}
+	for true == it.Previous() {
+		values = append(values, it.Value().(int))
+	}
+	if false == equalInts([]int{1, 2, 3, 4, 4, 3, 2, 1}, values) {
+		t.Fatal(values)
+	}
+	if false == equalInts([]int{1, 2, 3, 4}, linkedMapValues(m)) {
+		t.Fatal(linkedMapValues(m))
+	}
+}
@@ -0,0 +1,82 @@
+/*
+   Copyright 2017 Joseph Cumines
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+
+ */
+
+package simhash
+
+import (
+	"errors"
+)
+
+// lru is a Map with a maximum size, which is an access ordered linkedMap that removes the least recently used pair
+// when an insert would cause it to exceed the maximum size.
+type lru struct {
+	*linkedMap
+	maxSize int
+	onEvict func(key Key, value Value)
+}
+
+// evict removes the least recently used pairs until the map is within it's maximum size.
+func (m *lru) evict() {
+	for m.m.size > m.maxSize {
+		e := m.root.next
+		m.Remove(e.key)
+		if nil != m.onEvict {
+			m.onEvict(e.key, e.value)
+		}
+	}
+}
+
+func (m *lru) Put(key Key, value Value) Value {
+	v := m.linkedMap.Put(key, value)
+	m.evict()
+	return v
+}
+
+func (m *lru) ComputeIfAbsent(key Key, fn func(key Key) Value) Value {
+	v := m.linkedMap.ComputeIfAbsent(key, fn)
+	m.evict()
+	return v
+}
+
+func (m *lru) Compute(key Key, fn func(key Key, value Value) Value) Value {
+	v := m.linkedMap.Compute(key, fn)
+	m.evict()
+	return v
+}
+
+func (m *lru) Merge(key Key, value Value, fn func(old, new Value) Value) Value {
+	v := m.linkedMap.Merge(key, value, fn)
+	m.evict()
+	return v
+}
+
+// NewLRU creates a new Map that will hold at most maxSize pairs, evicting the least recently used pair when a new
+// key is added while it is full, where usage is as described by NewAccessOrderedLinkedMap.
+// If onEvict is not nil it will be called with the key and value of each evicted pair, after it has been removed,
+// e.g. to close the underlying resource. It will not be called for pairs that are removed or replaced directly.
+// The returned map also implements ComputeMap. It is not safe for concurrent use, and will panic if maxSize is
+// less than 1.
+func NewLRU(maxSize int, onEvict func(key Key, value Value)) Map {
+	if maxSize < 1 {
+		panic(errors.New("the maximum size of a lru map must be at least 1"))
+	}
+	return &lru{
+		newLinkedMap(true),
+		maxSize,
+		onEvict,
+	}
+}
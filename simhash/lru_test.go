@@ -0,0 +1,89 @@
+/*
+   Copyright 2017 Joseph Cumines
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+
+ */
+
+package simhash
+
+import (
+	"testing"
+)
+
+func TestNewLRU_panic(t *testing.T) {
+	defer func() {
+		err := recover().(error)
+		if nil == err || "the maximum size of a lru map must be at least 1" != err.Error() {
+			t.Fatal()
+		}
+	}()
+	NewLRU(0, nil)
+}
+
+func TestLRU_Put(t *testing.T) {
+	evicted := make([]int, 0)
+	m := NewLRU(3, func(key Key, value Value) {
+		if int(key.(testKeyInt)) != value.(int) {
+			t.Fatal()
+		}
+		evicted = append(evicted, value.(int))
+	})
+	for i := 1; i <= 3; i++ {
+		m.Put(testKeyInt(i), i)
+	}
+	if 3 != m.Size() || 0 != len(evicted) {
+		t.Fatal()
+	}
+	m.Get(testKeyInt(1))
+	m.Put(testKeyInt(4), 4)
+	if 3 != m.Size() || false == equalInts([]int{2}, evicted) || false == equalInts([]int{3, 1, 4}, linkedMapValues(m)) {
+		t.Fatal(evicted, linkedMapValues(m))
+	}
+	// replacing doesn't evict
+	m.Put(testKeyInt(3), 3)
+	if 3 != m.Size() || false == equalInts([]int{2}, evicted) || false == equalInts([]int{1, 4, 3}, linkedMapValues(m)) {
+		t.Fatal(evicted, linkedMapValues(m))
+	}
+	// neither does remove
+	m.Remove(testKeyInt(4))
+	m.Put(testKeyInt(5), 5)
+	m.Put(testKeyInt(6), 6)
+	if 3 != m.Size() || false == equalInts([]int{2, 1}, evicted) || false == equalInts([]int{3, 5, 6}, linkedMapValues(m)) {
+		t.Fatal(evicted, linkedMapValues(m))
+	}
+}
+
+func TestLRU_compute(t *testing.T) {
+	evicted := make([]int, 0)
+	m := NewLRU(1, func(key Key, value Value) {
+		evicted = append(evicted, value.(int))
+	}).(ComputeMap)
+	m.ComputeIfAbsent(testKeyInt(1), func(key Key) Value { return 1 })
+	m.ComputeIfAbsent(testKeyInt(2), func(key Key) Value { return 2 })
+	m.Compute(testKeyInt(3), func(key Key, value Value) Value { return 3 })
+	m.Merge(testKeyInt(4), 4, nil)
+	m.ComputeIfPresent(testKeyInt(4), func(key Key, value Value) Value { return 44 })
+	if 1 != m.Size() || 44 != m.Get(testKeyInt(4)).(int) || false == equalInts([]int{1, 2, 3}, evicted) {
+		t.Fatal(evicted)
+	}
+}
+
+func TestLRU_nilCallback(t *testing.T) {
+	m := NewLRU(1, nil)
+	m.Put(nil, 1)
+	m.Put(testKeyInt(1), 2)
+	if 1 != m.Size() || true == m.Contains(nil) || 2 != m.Get(testKeyInt(1)).(int) {
+		t.Fatal()
+	}
+}
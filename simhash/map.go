@@ -23,6 +23,8 @@ package simhash
 import (
 	"errors"
 	"fmt"
+
+	"github.com/joeycumines/go-hashmap/simhash/internal/hashbits"
 )
 
 // Map provides the specification for a hashmap type that behaves similarly to Java's implementation, and is exported
@@ -57,37 +59,139 @@ type Map interface {
 	// all implement fmt.Stringer, and return correctly serialized values, this will work correctly.
 	Serialize() map[string]interface{}
 
-	// Get a new Iterator for this map, which should be stable.
+	// Get a new Iterator for this map. Unless documented otherwise, iterators are fail-fast, and will panic with a
+	// *ConcurrentModificationError if the map has been structurally modified since they were created.
 	Iterator() Iterator
 }
 
+// ComputeMap extends Map with atomic read-modify-write operations, styled after the methods of the same names in
+// Java's Map interface. Each operation only needs to locate the key once, and will be atomic if the implementation
+// is safe for concurrent use. A nil value returned from any of the provided functions means that there should be no
+// value for the key, and will cause any existing value to be removed.
+// The provided functions must not access the map at all, including reading it, e.g. using Get or Contains, as the
+// map may be in an intermediate state, or locked, e.g. see NewConcurrentMap, where doing so would deadlock.
+type ComputeMap interface {
+	Map
+
+	// ComputeIfAbsent will, if the key doesn't exist in the map, store the result of fn as key, if it is not nil.
+	// It will return the current (existing or computed) value.
+	ComputeIfAbsent(key Key, fn func(key Key) Value) Value
+
+	// ComputeIfPresent will, if the key exists in the map, replace it's value with the result of fn, or remove it if
+	// the result was nil. It will return the new value, or nil.
+	ComputeIfPresent(key Key, fn func(key Key, value Value) Value) Value
+
+	// Compute will replace the value for the key with the result of fn, which receives the existing value, or nil,
+	// removing the key if the result was nil. It will return the new value, or nil.
+	Compute(key Key, fn func(key Key, value Value) Value) Value
+
+	// Merge will store value as key if the key doesn't exist in the map, otherwise the value will be replaced with
+	// the result of calling fn with the existing value and value, or removed if the result was nil. It will return
+	// the new value, or nil.
+	Merge(key Key, value Value, fn func(old, new Value) Value) Value
+}
+
 // A similar implementation to the HashMap in Java, this uses the underlying Go map but allows efficient (citation
 // needed) lookup of structs which either cannot be easily serialized or are expensive to do so.
 // Will most certainly break under unsynchronised concurrent write conditions, concurrent reads will be ok if the
 // map isn't changing (and the state is assured to be complete).
+// Buckets that grow large (due to poorly distributed hashes) are indexed by a tree, if their keys implement
+// Comparable, see bucketTree. The buckets are keyed by the hash of each key, which may be mixed with a seed, see
+// WithHashSeed.
 type hashMap struct {
 	m    map[int][]Pair
 	size int
+	// trees indexes large buckets, where a nil tree marks a bucket that cannot be treeified, see treeify.
+	trees         map[int]*bucketTree
+	seed          uint64
+	seeded        bool
+	bucketLimit   int
+	onBucketLimit func(err *BucketLimitError)
+	// modCount is incremented whenever a key is added or removed, and is used to detect modification during iteration.
+	modCount      int
+	weakIterators bool
+}
+
+// hashOf returns the hash of key, where a nil key has a hash of 0, and the hash of a Key64 is folded into an int, if
+// an int is smaller than 64 bits.
+func hashOf(key Key) int {
+	if k, ok := key.(Key64); true == ok {
+		return hashbits.Fold(k.Hash64())
+	}
+	if nil == key {
+		return 0
+	}
+	return key.Hash()
+}
+
+// hash64Of returns the 64 bit hash of key, which is the hash of key sign extended, unless it implements Key64.
+func hash64Of(key Key) uint64 {
+	if k, ok := key.(Key64); true == ok {
+		return k.Hash64()
+	}
+	return uint64(hashOf(key))
 }
 
 func (m *hashMap) lookup(key Key) (int, int, bool) {
-	h := 0
-	if nil != key {
-		h = key.Hash()
+	h := m.hash(key)
+	if i, ok := m.find(h, key); true == ok {
+		return h, i, true
+	}
+	return 0, 0, false
+}
+
+// find returns the index of key within the bucket for hash h.
+func (m *hashMap) find(h int, key Key) (int, bool) {
+	if i, ok, used := m.treeFind(h, key); true == used {
+		return i, ok
 	}
 	pairs, ok := m.m[h]
 	if false == ok || nil == pairs {
-		return 0, 0, false
+		return 0, false
 	}
 	for i, pair := range pairs {
 		if nil == pair {
 			continue
 		}
 		if k := pair.Key(); (nil == key && nil == k) || (nil != key && nil != k && key.Equals(k)) {
-			return h, i, true
+			return i, true
 		}
 	}
-	return 0, 0, false
+	return 0, false
+}
+
+// insert appends pair to the bucket for hash h, it must not already contain the key.
+func (m *hashMap) insert(h int, pair Pair) {
+	if pairs, ok := m.m[h]; false == ok || nil == pairs {
+		m.m[h] = make([]Pair, 0, 1)
+	}
+	m.m[h] = append(m.m[h], pair)
+	m.size++
+	m.modCount++
+	m.treeInsert(h, pair)
+	m.checkBucketLimit(h, pair.Key())
+}
+
+// replaceAt replaces the pair at index i of the bucket for hash h, which must have an equal key.
+func (m *hashMap) replaceAt(h int, i int, pair Pair) {
+	m.m[h][i] = pair
+	m.treeReplace(h, pair)
+}
+
+// removeAt removes the pair at index i of the bucket for hash h, by swapping it with the last pair in the bucket,
+// and returns it's value.
+func (m *hashMap) removeAt(h int, i int) Value {
+	m.treeRemove(h, i)
+	v := m.m[h][i].Value()
+	m.m[h][i] = m.m[h][len(m.m[h])-1]
+	m.m[h][len(m.m[h])-1] = nil
+	m.m[h] = m.m[h][:len(m.m[h])-1]
+	if 0 == len(m.m[h]) {
+		delete(m.m, h)
+	}
+	m.size--
+	m.modCount++
+	return v
 }
 
 func (m *hashMap) Contains(key Key) bool {
@@ -104,20 +208,13 @@ func (m *hashMap) Get(key Key) Value {
 }
 
 func (m *hashMap) Put(key Key, value Value) Value {
-	if h, i, ok := m.lookup(key); true == ok {
+	h := m.hash(key)
+	if i, ok := m.find(h, key); true == ok {
 		v := m.m[h][i].Value()
-		m.m[h][i] = NewPair(key, value)
+		m.replaceAt(h, i, NewPair(key, value))
 		return v
 	}
-	h := 0
-	if nil != key {
-		h = key.Hash()
-	}
-	if pairs, ok := m.m[h]; false == ok || nil == pairs {
-		m.m[h] = make([]Pair, 0, 1)
-	}
-	m.m[h] = append(m.m[h], NewPair(key, value))
-	m.size++
+	m.insert(h, NewPair(key, value))
 	return nil
 }
 
@@ -126,15 +223,72 @@ func (m *hashMap) Remove(key Key) Value {
 	if false == ok {
 		return nil
 	}
-	v := m.m[h][i].Value()
-	m.m[h][i] = m.m[h][len(m.m[h])-1]
-	m.m[h][len(m.m[h])-1] = nil
-	m.m[h] = m.m[h][:len(m.m[h])-1]
-	if 0 == len(m.m[h]) {
-		delete(m.m, h)
+	return m.removeAt(h, i)
+}
+
+func (m *hashMap) ComputeIfAbsent(key Key, fn func(key Key) Value) Value {
+	h := m.hash(key)
+	if i, ok := m.find(h, key); true == ok {
+		return m.m[h][i].Value()
 	}
-	m.size--
-	return v
+	value := fn(key)
+	if nil != value {
+		m.insert(h, NewPair(key, value))
+	}
+	return value
+}
+
+func (m *hashMap) ComputeIfPresent(key Key, fn func(key Key, value Value) Value) Value {
+	h := m.hash(key)
+	i, ok := m.find(h, key)
+	if false == ok {
+		return nil
+	}
+	value := fn(key, m.m[h][i].Value())
+	if nil == value {
+		m.removeAt(h, i)
+		return nil
+	}
+	m.replaceAt(h, i, NewPair(key, value))
+	return value
+}
+
+func (m *hashMap) Compute(key Key, fn func(key Key, value Value) Value) Value {
+	h := m.hash(key)
+	i, ok := m.find(h, key)
+	var old Value
+	if true == ok {
+		old = m.m[h][i].Value()
+	}
+	value := fn(key, old)
+	switch {
+	case nil == value && true == ok:
+		m.removeAt(h, i)
+	case nil == value:
+	case true == ok:
+		m.replaceAt(h, i, NewPair(key, value))
+	default:
+		m.insert(h, NewPair(key, value))
+	}
+	return value
+}
+
+func (m *hashMap) Merge(key Key, value Value, fn func(old, new Value) Value) Value {
+	h := m.hash(key)
+	i, ok := m.find(h, key)
+	if false == ok {
+		if nil != value {
+			m.insert(h, NewPair(key, value))
+		}
+		return value
+	}
+	value = fn(m.m[h][i].Value(), value)
+	if nil == value {
+		m.removeAt(h, i)
+		return nil
+	}
+	m.replaceAt(h, i, NewPair(key, value))
+	return value
 }
 
 func (m *hashMap) Keys() []Key {
@@ -230,9 +384,27 @@ func (m *hashMap) Iterator() Iterator {
 		0,
 		true,
 		false,
+		m.modCount,
 	}
 }
 
-func NewMap() Map {
-	return &hashMap{make(map[int][]Pair), 0}
+// NewMap creates a new, empty, Map, which also implements ComputeMap. It is not safe for concurrent use, see
+// NewConcurrentMap. The implementation may be configured using options, and by default uses a random hash seed, see
+// WithRandomHashSeed.
+func NewMap(options ...Option) Map {
+	o := newMapOptions(options)
+	if true == o.openAddressing {
+		m := newSwissMap()
+		m.seed = o.seed
+		m.weakIterators = o.weakIterators
+		return m
+	}
+	return &hashMap{
+		m:             make(map[int][]Pair),
+		seed:          o.seed,
+		seeded:        o.seeded,
+		bucketLimit:   o.bucketLimit,
+		onBucketLimit: o.onBucketLimit,
+		weakIterators: o.weakIterators,
+	}
 }
@@ -43,7 +43,7 @@ func TestHashMap_lookup_missingKey(t *testing.T) {
 }
 
 func TestHashMap_lookup_noItems(t *testing.T) {
-	m := &hashMap{map[int][]Pair{4: {nil}}, 0}
+	m := &hashMap{m: map[int][]Pair{4: {nil}}, size: 0}
 	h, i, ok := m.lookup(testKeyInt(4))
 	if 0 != h || 0 != i || false != ok {
 		t.Fatal()
@@ -51,7 +51,7 @@ func TestHashMap_lookup_noItems(t *testing.T) {
 }
 
 func TestHashMap_lookup(t *testing.T) {
-	m := &hashMap{map[int][]Pair{4: {nil, NewPair(nil, 10), NewPair(testKeyInt(4), 20)}}, 0}
+	m := &hashMap{m: map[int][]Pair{4: {nil, NewPair(nil, 10), NewPair(testKeyInt(4), 20)}}, size: 0}
 	h, i, ok := m.lookup(testKeyInt(4))
 	if 4 != h || 2 != i || true != ok {
 		t.Fatal()
@@ -59,7 +59,7 @@ func TestHashMap_lookup(t *testing.T) {
 }
 
 func TestHashMap_Size(t *testing.T) {
-	m := &hashMap{nil, 55}
+	m := &hashMap{m: nil, size: 55}
 	if 55 != m.Size() {
 		t.Fatal()
 	}
@@ -73,7 +73,7 @@ func TestNewMap(t *testing.T) {
 }
 
 func TestHashMap_Contains(t *testing.T) {
-	m := &hashMap{map[int][]Pair{4: {nil, NewPair(nil, 10), NewPair(testKeyInt(4), 20)}}, 0}
+	m := &hashMap{m: map[int][]Pair{4: {nil, NewPair(nil, 10), NewPair(testKeyInt(4), 20)}}, size: 0}
 	if true != m.Contains(testKeyInt(4)) {
 		t.Fatal()
 	}
@@ -84,21 +84,21 @@ func TestHashMap_Contains(t *testing.T) {
 }
 
 func TestHashMap_Contains_empty(t *testing.T) {
-	m := &hashMap{map[int][]Pair{4: {nil}}, 0}
+	m := &hashMap{m: map[int][]Pair{4: {nil}}, size: 0}
 	if false != m.Contains(testKeyInt(4)) && false != m.Contains(nil) {
 		t.Fatal()
 	}
 }
 
 func TestHashMap_Get(t *testing.T) {
-	m := &hashMap{map[int][]Pair{4: {nil, NewPair(nil, 10), NewPair(testKeyInt(4), 20)}}, 0}
+	m := &hashMap{m: map[int][]Pair{4: {nil, NewPair(nil, 10), NewPair(testKeyInt(4), 20)}}, size: 0}
 	if 20 != m.Get(testKeyInt(4)).(int) {
 		t.Fatal()
 	}
 }
 
 func TestHashMap_Get_empty(t *testing.T) {
-	m := &hashMap{map[int][]Pair{4: {nil}}, 0}
+	m := &hashMap{m: map[int][]Pair{4: {nil}}, size: 0}
 	if nil != m.Get(testKeyInt(4)) {
 		t.Fatal()
 	}
@@ -126,7 +126,7 @@ func TestHashMap_Remove_nilKey(t *testing.T) {
 }
 
 func TestHashMap_Put_existing(t *testing.T) {
-	m := &hashMap{map[int][]Pair{4: {nil, NewPair(nil, 10), NewPair(testKeyInt(4), 20)}}, 2}
+	m := &hashMap{m: map[int][]Pair{4: {nil, NewPair(nil, 10), NewPair(testKeyInt(4), 20)}}, size: 2}
 	if 20 != m.Put(testKeyInt(4), 22).(int) ||
 		2 != m.size ||
 		22 != m.m[4][2].Value().(int) ||
@@ -140,7 +140,7 @@ func TestHashMap_Put_existing(t *testing.T) {
 }
 
 func TestHashMap_Put_noHash(t *testing.T) {
-	m := NewMap().(*hashMap)
+	m := NewMap(WithoutHashSeed()).(*hashMap)
 	if nil != m.Put(testKeyInt(4), "s") ||
 		1 != len(m.m) ||
 		1 != len(m.m[4]) ||
@@ -153,7 +153,7 @@ func TestHashMap_Put_noHash(t *testing.T) {
 }
 
 func TestHashMap_Put_nilSlice(t *testing.T) {
-	m := NewMap().(*hashMap)
+	m := NewMap(WithoutHashSeed()).(*hashMap)
 	m.m[4] = nil
 	if nil != m.Put(testKeyInt(4), "s") ||
 		1 != len(m.m) ||
@@ -180,7 +180,7 @@ func TestHashMap_Put(t *testing.T) {
 }
 
 func TestHashMap_Remove(t *testing.T) {
-	m := NewMap().(*hashMap)
+	m := NewMap(WithoutHashSeed()).(*hashMap)
 	m.Put(testKeyInt(67), "67")
 	m.Put(testKeyInt(68), "68")
 	if nil != m.Remove(nil) {
@@ -209,7 +209,7 @@ func TestHashMap_Remove(t *testing.T) {
 }
 
 func genTestStructureHashMap() *hashMap {
-	m := NewMap().(*hashMap)
+	m := NewMap(WithoutHashSeed()).(*hashMap)
 	for x := 1; x <= 3; x++ {
 		for y := 1; y <= 3; y++ {
 			i := x*10 + y
@@ -430,3 +430,92 @@ func TestHashMap_Iterator(t *testing.T) {
 		t.Fatalf("unexpected: %v", valueList)
 	}
 }
+
+func TestHashMap_ComputeIfAbsent(t *testing.T) {
+	m := NewMap().(*hashMap)
+	calls := 0
+	fn := func(key Key) Value {
+		calls++
+		return int(key.(testKeyInt)) * 2
+	}
+	if 8 != m.ComputeIfAbsent(testKeyInt(4), fn).(int) || 1 != calls || 1 != m.Size() || 8 != m.Get(testKeyInt(4)).(int) {
+		t.Fatal()
+	}
+	if 8 != m.ComputeIfAbsent(testKeyInt(4), fn).(int) || 1 != calls || 1 != m.Size() {
+		t.Fatal()
+	}
+	if nil != m.ComputeIfAbsent(nil, func(key Key) Value { return nil }) || 1 != m.Size() || true == m.Contains(nil) {
+		t.Fatal()
+	}
+	var _ ComputeMap = m
+}
+
+func TestHashMap_ComputeIfPresent(t *testing.T) {
+	m := NewMap().(*hashMap)
+	fn := func(key Key, value Value) Value {
+		if 3 == value.(int) {
+			return nil
+		}
+		return value.(int) + 1
+	}
+	if nil != m.ComputeIfPresent(testKeyInt(4), fn) || 0 != m.Size() {
+		t.Fatal()
+	}
+	m.Put(testKeyInt(4), 1)
+	if 2 != m.ComputeIfPresent(testKeyInt(4), fn).(int) || 2 != m.Get(testKeyInt(4)).(int) {
+		t.Fatal()
+	}
+	if 3 != m.ComputeIfPresent(testKeyInt(4), fn).(int) || 1 != m.Size() {
+		t.Fatal()
+	}
+	if nil != m.ComputeIfPresent(testKeyInt(4), fn) || 0 != m.Size() || true == m.Contains(testKeyInt(4)) || 0 != len(m.m) {
+		t.Fatal()
+	}
+}
+
+func TestHashMap_Compute(t *testing.T) {
+	m := NewMap().(*hashMap)
+	fn := func(key Key, value Value) Value {
+		if nil == value {
+			return 1
+		}
+		if 2 == value.(int) {
+			return nil
+		}
+		return value.(int) + 1
+	}
+	if nil != m.Compute(nil, func(key Key, value Value) Value { return nil }) || 0 != m.Size() {
+		t.Fatal()
+	}
+	if 1 != m.Compute(nil, fn).(int) || 1 != m.Size() || 1 != m.Get(nil).(int) {
+		t.Fatal()
+	}
+	if 2 != m.Compute(nil, fn).(int) || 1 != m.Size() || 2 != m.Get(nil).(int) {
+		t.Fatal()
+	}
+	if nil != m.Compute(nil, fn) || 0 != m.Size() || true == m.Contains(nil) {
+		t.Fatal()
+	}
+}
+
+func TestHashMap_Merge(t *testing.T) {
+	m := NewMap().(*hashMap)
+	fn := func(old, new Value) Value {
+		if "" == new.(string) {
+			return nil
+		}
+		return old.(string) + new.(string)
+	}
+	if "a" != m.Merge(testKeyInt(1), "a", fn).(string) || 1 != m.Size() {
+		t.Fatal()
+	}
+	if "ab" != m.Merge(testKeyInt(1), "b", fn).(string) || "ab" != m.Get(testKeyInt(1)).(string) || 1 != m.Size() {
+		t.Fatal()
+	}
+	if nil != m.Merge(testKeyInt(1), "", fn) || 0 != m.Size() {
+		t.Fatal()
+	}
+	if nil != m.Merge(testKeyInt(1), nil, fn) || 0 != m.Size() {
+		t.Fatal()
+	}
+}
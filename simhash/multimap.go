@@ -0,0 +1,275 @@
+/*
+   Copyright 2017 Joseph Cumines
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+
+ */
+
+package simhash
+
+import (
+	"reflect"
+)
+
+// MultiMap is a map that may store multiple values for each key, styled after Guava's Multimap. The values for each
+// key are kept in the order they were added, and how duplicate values are handled depends on the implementation.
+type MultiMap interface {
+	// Contains will return true if the key has at least one value in the multimap.
+	Contains(key Key) bool
+
+	// ContainsEntry will return true if value is one of the values for key.
+	ContainsEntry(key Key, value Value) bool
+
+	// Get will return a new slice containing all the values for key, or nil if there are none.
+	Get(key Key) []Value
+
+	// Put will add value to the values for key, and will return true if the multimap was changed.
+	Put(key Key, value Value) bool
+
+	// RemoveValue will remove a single occurrence of value from the values for key, and will return true if it
+	// existed.
+	RemoveValue(key Key, value Value) bool
+
+	// RemoveAll will remove all the values for key, and will return them, or nil if there were none.
+	RemoveAll(key Key) []Value
+
+	// Keys returns a slice containing all the distinct keys in the multimap.
+	Keys() []Key
+
+	// KeyCount returns the number of distinct keys in the multimap.
+	KeyCount() int
+
+	// ValueCount returns the total number of values in the multimap, across all keys.
+	ValueCount() int
+
+	// Entries returns an Iterator over a snapshot of every key-value pair in the multimap, where a key with multiple
+	// values will appear once for each value.
+	Entries() Iterator
+}
+
+// multiMap implements MultiMap using a hashMap, with the values for each key stored in a multiValues, which is never
+// exposed directly. It has the same concurrency restrictions as hashMap.
+type multiMap struct {
+	m      *hashMap
+	size   int
+	unique bool
+}
+
+// multiValues holds the values for a key, in the order they were added, where set indexes them, if they are unique.
+type multiValues struct {
+	list []Value
+	set  *valueSet
+}
+
+// valueSet indexes the distinct values for a key, for NewSetMultiMap, so duplicates are found without a scan. Values
+// that implement Key are stored in keys, and any other values in values, using ==, while values that are never equal
+// to themselves, e.g. slices, are not stored at all, as they can never be duplicates. Both are created on demand.
+type valueSet struct {
+	keys   *hashMap
+	values map[Value]struct{}
+}
+
+// valuesEqual compares two values, using Equals if both are keys, or ==, where values that are not comparable are
+// never equal, including values of comparable types that hold values that are not, e.g. a slice in an interface field.
+func valuesEqual(a, b Value) (equal bool) {
+	if nil == a || nil == b {
+		return nil == a && nil == b
+	}
+	if aKey, ok := a.(Key); true == ok {
+		bKey, ok := b.(Key)
+		return true == ok && aKey.Hash() == bKey.Hash() && aKey.Equals(bKey)
+	}
+	t := reflect.TypeOf(a)
+	if t != reflect.TypeOf(b) || false == t.Comparable() {
+		return false
+	}
+	defer func() {
+		if nil != recover() {
+			equal = false
+		}
+	}()
+	return a == b
+}
+
+func (s *valueSet) contains(value Value) bool {
+	if k, ok := value.(Key); true == ok {
+		return nil != s.keys && true == s.keys.Contains(k)
+	}
+	if false == valuesEqual(value, value) {
+		return false
+	}
+	_, ok := s.values[value]
+	return ok
+}
+
+// add adds value to the set, returning false if it was already present.
+func (s *valueSet) add(value Value) bool {
+	if true == s.contains(value) {
+		return false
+	}
+	if k, ok := value.(Key); true == ok {
+		if nil == s.keys {
+			s.keys = NewMap().(*hashMap)
+		}
+		s.keys.Put(k, nil)
+	} else if true == valuesEqual(value, value) {
+		if nil == s.values {
+			s.values = make(map[Value]struct{})
+		}
+		s.values[value] = struct{}{}
+	}
+	return true
+}
+
+// remove removes value from the set, returning false if it was not present.
+func (s *valueSet) remove(value Value) bool {
+	if false == s.contains(value) {
+		return false
+	}
+	if k, ok := value.(Key); true == ok {
+		s.keys.Remove(k)
+	} else {
+		delete(s.values, value)
+	}
+	return true
+}
+
+func indexOfValue(values []Value, value Value) int {
+	for i, v := range values {
+		if true == valuesEqual(v, value) {
+			return i
+		}
+	}
+	return -1
+}
+
+func (m *multiMap) values(key Key) *multiValues {
+	if v := m.m.Get(key); nil != v {
+		return v.(*multiValues)
+	}
+	return nil
+}
+
+func (m *multiMap) Contains(key Key) bool {
+	return m.m.Contains(key)
+}
+
+func (m *multiMap) ContainsEntry(key Key, value Value) bool {
+	values := m.values(key)
+	if nil == values {
+		return false
+	}
+	if nil != values.set {
+		return values.set.contains(value)
+	}
+	return -1 != indexOfValue(values.list, value)
+}
+
+func (m *multiMap) Get(key Key) []Value {
+	values := m.values(key)
+	if nil == values {
+		return nil
+	}
+	return append([]Value(nil), values.list...)
+}
+
+func (m *multiMap) Put(key Key, value Value) bool {
+	var values *multiValues
+	h := m.m.hash(key)
+	if i, ok := m.m.find(h, key); true == ok {
+		values = m.m.m[h][i].Value().(*multiValues)
+	} else {
+		values = &multiValues{}
+		if true == m.unique {
+			values.set = &valueSet{}
+		}
+		m.m.insert(h, NewPair(key, values))
+	}
+	if nil != values.set && false == values.set.add(value) {
+		return false
+	}
+	values.list = append(values.list, value)
+	m.size++
+	return true
+}
+
+func (m *multiMap) RemoveValue(key Key, value Value) bool {
+	h, i, ok := m.m.lookup(key)
+	if false == ok {
+		return false
+	}
+	values := m.m.m[h][i].Value().(*multiValues)
+	if nil != values.set && false == values.set.remove(value) {
+		return false
+	}
+	x := indexOfValue(values.list, value)
+	if -1 == x {
+		return false
+	}
+	m.size--
+	if 1 == len(values.list) {
+		m.m.removeAt(h, i)
+		return true
+	}
+	copy(values.list[x:], values.list[x+1:])
+	values.list[len(values.list)-1] = nil
+	values.list = values.list[:len(values.list)-1]
+	return true
+}
+
+func (m *multiMap) RemoveAll(key Key) []Value {
+	v := m.m.Remove(key)
+	if nil == v {
+		return nil
+	}
+	values := v.(*multiValues).list
+	m.size -= len(values)
+	return values
+}
+
+func (m *multiMap) Keys() []Key {
+	return m.m.Keys()
+}
+
+func (m *multiMap) KeyCount() int {
+	return m.m.Size()
+}
+
+func (m *multiMap) ValueCount() int {
+	return m.size
+}
+
+func (m *multiMap) Entries() Iterator {
+	pairs := make([]Pair, 0, m.size)
+	for _, pair := range m.m.Pairs() {
+		for _, value := range pair.Value().(*multiValues).list {
+			pairs = append(pairs, NewPair(pair.Key(), value))
+		}
+	}
+	return newSliceIterator(pairs)
+}
+
+// NewListMultiMap creates a new MultiMap which allows duplicate values for a key, where Put will always add the
+// value. It is not safe for concurrent use.
+func NewListMultiMap() MultiMap {
+	return &multiMap{NewMap().(*hashMap), 0, false}
+}
+
+// NewSetMultiMap creates a new MultiMap which will not store duplicate values for a key, where Put will not add a
+// value that is equal to an existing value for the key. Values that implement Key will be compared using Equals,
+// and any other values using ==, where values that are not comparable are never equal, and are always added. The
+// values for each key are indexed, so Put and ContainsEntry don't need to scan them. It is not safe for concurrent
+// use.
+func NewSetMultiMap() MultiMap {
+	return &multiMap{NewMap().(*hashMap), 0, true}
+}
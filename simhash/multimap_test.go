@@ -0,0 +1,195 @@
+/*
+   Copyright 2017 Joseph Cumines
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+
+ */
+
+package simhash
+
+import (
+	"math"
+	"testing"
+)
+
+// testMultiMapValue is comparable, but comparing it will panic if v holds a value that is not.
+type testMultiMapValue struct {
+	v interface{}
+}
+
+func multiMapInts(values []Value) []int {
+	ints := make([]int, 0)
+	for _, v := range values {
+		ints = append(ints, v.(int))
+	}
+	return ints
+}
+
+func TestValuesEqual(t *testing.T) {
+	for i, c := range []struct {
+		a, b     Value
+		expected bool
+	}{
+		{nil, nil, true},
+		{nil, 1, false},
+		{1, nil, false},
+		{1, 1, true},
+		{1, 2, false},
+		{1, int64(1), false},
+		{"a", "a", true},
+		{testKeyInt(1), testKeyInt(1), true},
+		{testKeyInt(1), testKeyInt(2), false},
+		{testKeyInt(1), 1, false},
+		{1, testKeyInt(1), false},
+		{[]int{1}, []int{1}, false},
+		{testMultiMapValue{1}, testMultiMapValue{1}, true},
+		{testMultiMapValue{1}, testMultiMapValue{2}, false},
+		{testMultiMapValue{[]int{1}}, testMultiMapValue{[]int{1}}, false},
+		{testMultiMapValue{[]int{1}}, testMultiMapValue{1}, false},
+		{math.NaN(), math.NaN(), false},
+	} {
+		if c.expected != valuesEqual(c.a, c.b) {
+			t.Error(i)
+		}
+	}
+}
+
+func TestListMultiMap(t *testing.T) {
+	m := NewListMultiMap()
+	if true != m.Put(testKeyInt(1), 1) || true != m.Put(testKeyInt(1), 2) || true != m.Put(testKeyInt(1), 1) || true != m.Put(nil, 3) {
+		t.Fatal()
+	}
+	if 2 != m.KeyCount() || 4 != m.ValueCount() || 2 != len(m.Keys()) {
+		t.Fatal()
+	}
+	if false == equalInts([]int{1, 2, 1}, multiMapInts(m.Get(testKeyInt(1)))) || false == equalInts([]int{3}, multiMapInts(m.Get(nil))) {
+		t.Fatal()
+	}
+	if nil != m.Get(testKeyInt(2)) || true == m.Contains(testKeyInt(2)) || true != m.Contains(nil) {
+		t.Fatal()
+	}
+	if true != m.ContainsEntry(testKeyInt(1), 2) || true == m.ContainsEntry(testKeyInt(1), 3) || true == m.ContainsEntry(testKeyInt(2), 1) {
+		t.Fatal()
+	}
+	// the returned slice is a copy
+	m.Get(testKeyInt(1))[0] = 99
+	if 1 != m.Get(testKeyInt(1))[0].(int) {
+		t.Fatal()
+	}
+	if true != m.RemoveValue(testKeyInt(1), 1) || false == equalInts([]int{2, 1}, multiMapInts(m.Get(testKeyInt(1)))) || 3 != m.ValueCount() {
+		t.Fatal()
+	}
+	if false != m.RemoveValue(testKeyInt(1), 5) || false != m.RemoveValue(testKeyInt(5), 1) || 3 != m.ValueCount() {
+		t.Fatal()
+	}
+	if true != m.RemoveValue(nil, 3) || true == m.Contains(nil) || 1 != m.KeyCount() || 2 != m.ValueCount() {
+		t.Fatal()
+	}
+	if false == equalInts([]int{2, 1}, multiMapInts(m.RemoveAll(testKeyInt(1)))) || nil != m.RemoveAll(testKeyInt(1)) {
+		t.Fatal()
+	}
+	if 0 != m.KeyCount() || 0 != m.ValueCount() {
+		t.Fatal()
+	}
+}
+
+func TestSetMultiMap(t *testing.T) {
+	m := NewSetMultiMap()
+	if true != m.Put(testKeyInt(1), testKeyInt(10)) || false != m.Put(testKeyInt(1), testKeyInt(10)) ||
+		true != m.Put(testKeyInt(1), testKeyInt(20)) || true != m.Put(testKeyInt(2), testKeyInt(10)) {
+		t.Fatal()
+	}
+	if 2 != m.KeyCount() || 3 != m.ValueCount() || 2 != len(m.Get(testKeyInt(1))) {
+		t.Fatal()
+	}
+	if true != m.RemoveValue(testKeyInt(1), testKeyInt(10)) || true != m.Put(testKeyInt(1), testKeyInt(10)) {
+		t.Fatal()
+	}
+	values := m.Get(testKeyInt(1))
+	if 2 != len(values) || 20 != int(values[0].(testKeyInt)) || 10 != int(values[1].(testKeyInt)) {
+		t.Fatal(values)
+	}
+}
+
+func TestSetMultiMap_values(t *testing.T) {
+	m := NewSetMultiMap()
+	k := testKeyInt(1)
+	for _, c := range []struct {
+		value   Value
+		changed bool
+	}{
+		{nil, true},
+		{nil, false},
+		{1, true},
+		{int64(1), true},
+		{1, false},
+		{"a", true},
+		{"a", false},
+		{testKeyInt(1), true},
+		{testKeyInt(1), false},
+		{testMultiMapValue{1}, true},
+		{testMultiMapValue{1}, false},
+		// values that are never equal are always added
+		{testMultiMapValue{[]int{1}}, true},
+		{testMultiMapValue{[]int{1}}, true},
+		{[]int{1}, true},
+		{math.NaN(), true},
+		{math.NaN(), true},
+	} {
+		if c.changed != m.Put(k, c.value) {
+			t.Fatalf("%#v", c.value)
+		}
+	}
+	if 11 != m.ValueCount() || 11 != len(m.Get(k)) {
+		t.Fatal(m.ValueCount())
+	}
+	for _, value := range []Value{nil, 1, int64(1), "a", testKeyInt(1), testMultiMapValue{1}} {
+		if true != m.ContainsEntry(k, value) {
+			t.Fatalf("%#v", value)
+		}
+	}
+	for _, value := range []Value{2, "b", testKeyInt(2), testMultiMapValue{2}, testMultiMapValue{[]int{1}}, math.NaN()} {
+		if true == m.ContainsEntry(k, value) || true == m.RemoveValue(k, value) {
+			t.Fatalf("%#v", value)
+		}
+	}
+	if true != m.RemoveValue(k, "a") || true == m.ContainsEntry(k, "a") || true != m.RemoveValue(k, testKeyInt(1)) ||
+		true == m.ContainsEntry(k, testKeyInt(1)) || 9 != m.ValueCount() {
+		t.Fatal()
+	}
+	if true != m.Put(k, "a") || "a" != m.Get(k)[9] {
+		t.Fatal()
+	}
+	if 10 != len(m.RemoveAll(k)) || true == m.ContainsEntry(k, 1) || true != m.Put(k, 1) || 1 != m.ValueCount() {
+		t.Fatal()
+	}
+}
+
+func TestMultiMap_Entries(t *testing.T) {
+	m := NewListMultiMap()
+	m.Put(testKeyInt(1), 11)
+	m.Put(testKeyInt(1), 12)
+	m.Put(testKeyInt(2), 21)
+	it := m.Entries()
+	m.Put(testKeyInt(3), 31)
+	count := 0
+	for true == it.Next() {
+		if int(it.Key().(testKeyInt)) != it.Value().(int)/10 {
+			t.Fatal()
+		}
+		count++
+	}
+	if 3 != count {
+		t.Fatal(count)
+	}
+}
@@ -0,0 +1,127 @@
+/*
+   Copyright 2017 Joseph Cumines
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+
+ */
+
+package simhash
+
+import (
+	"errors"
+
+	"github.com/joeycumines/go-hashmap/simhash/internal/hashbits"
+)
+
+// Option configures a Map created by NewMap or NewConcurrentMap.
+type Option func(o *mapOptions)
+
+type mapOptions struct {
+	openAddressing bool
+	seed           uint64
+	seeded         bool
+	unseeded       bool
+	bucketLimit    int
+	onBucketLimit  func(err *BucketLimitError)
+	weakIterators  bool
+}
+
+func newMapOptions(options []Option) *mapOptions {
+	o := &mapOptions{}
+	for _, option := range options {
+		option(o)
+	}
+	if false == o.seeded && false == o.unseeded {
+		o.seed = hashbits.RandomSeed()
+		o.seeded = true
+	}
+	if 0 != o.bucketLimit && true == o.openAddressing {
+		panic(errors.New("the bucket limit is not supported with open addressing"))
+	}
+	return o
+}
+
+// WithOpenAddressing selects an open addressing implementation, styled after Google's SwissTable, which stores keys
+// and values inline, in groups of slots that are probed using a byte of control data per slot. Compared to the
+// default implementation it avoids allocating for each Put, and for each distinct hash, and usually performs better
+// for large maps. Like the default implementation, it's Iterator is fail-fast, see WithWeaklyConsistentIterators.
+func WithOpenAddressing() Option {
+	return func(o *mapOptions) {
+		o.openAddressing = true
+	}
+}
+
+// WithHashSeed mixes seed into the hash of every key, using a strong finaliser, before it is used to place the key.
+// Keys with distinct hashes will still have distinct placements, but an attacker who can predict the hashes of the
+// keys they control can no longer predict their placement, without also knowing the seed.
+// Keys with identical hashes will always collide, regardless of the seed, see WithBucketLimit.
+// By default, maps use a random seed, see WithRandomHashSeed, so this is only necessary for reproducible placement.
+func WithHashSeed(seed uint64) Option {
+	return func(o *mapOptions) {
+		o.seed = seed
+		o.seeded = true
+		o.unseeded = false
+	}
+}
+
+// WithRandomHashSeed is the same as WithHashSeed, using a seed read from crypto/rand when the map is created. This is
+// the default, unless WithHashSeed or WithoutHashSeed is used.
+func WithRandomHashSeed() Option {
+	return func(o *mapOptions) {
+		o.seed = hashbits.RandomSeed()
+		o.seeded = true
+		o.unseeded = false
+	}
+}
+
+// WithoutHashSeed disables the random hash seed that is used by default, so keys are placed using their hash alone,
+// which is cheaper, and deterministic, but allows an attacker who can predict the hashes of the keys they control to
+// also predict their placement. It should not be used for maps which store keys from an untrusted source.
+func WithoutHashSeed() Option {
+	return func(o *mapOptions) {
+		o.seed = 0
+		o.seeded = false
+		o.unseeded = true
+	}
+}
+
+// WithBucketLimit calls fn whenever the number of keys sharing a single hash grows beyond limit, which indicates
+// either a very poor hash function, or a hash flooding attack, and can be used to log or count such events, or to
+// reject further keys from the source. It will only be called again for the same hash if the number of keys first
+// drops back to the limit. The map itself is still modified, and fn must not access the map at all, the same as the
+// functions passed to ComputeMap.
+// It will panic if limit is less than 1 or fn is nil, and creating a map will panic if it is combined with
+// WithOpenAddressing, which has no buckets to limit.
+func WithBucketLimit(limit int, fn func(err *BucketLimitError)) Option {
+	if limit < 1 {
+		panic(errors.New("the bucket limit must be at least 1"))
+	}
+	if nil == fn {
+		panic(errors.New("the bucket limit callback must not be nil"))
+	}
+	return func(o *mapOptions) {
+		o.bucketLimit = limit
+		o.onBucketLimit = fn
+	}
+}
+
+// WithWeaklyConsistentIterators disables the detection of modification during iteration, so the map may be modified
+// while it is being iterated, without the iterator panicking with a *ConcurrentModificationError. Each step of the
+// iterator will still return a pair that is currently in the map, but pairs may be skipped, or returned more than
+// once, if keys are added or removed. Removing the current key, while iterating forwards, will never cause another
+// key to be returned more than once.
+func WithWeaklyConsistentIterators() Option {
+	return func(o *mapOptions) {
+		o.weakIterators = true
+	}
+}
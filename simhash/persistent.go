@@ -0,0 +1,473 @@
+/*
+   Copyright 2017 Joseph Cumines
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+
+ */
+
+package simhash
+
+import (
+	"fmt"
+	"math/bits"
+)
+
+// PersistentMap is an immutable map, where each modification returns a new map, which shares most of it's structure
+// with the original, making both modifications and snapshots cheap. It is safe for concurrent use.
+type PersistentMap interface {
+	// Contains will return true if the key exists in the map.
+	Contains(key Key) bool
+
+	// Get will return the value if it exists in the map, or nil if it doesn't.
+	Get(key Key) Value
+
+	// With returns a new map which stores value as key, leaving this map unchanged.
+	With(key Key, value Value) PersistentMap
+
+	// Without returns a new map which does not contain key, leaving this map unchanged.
+	Without(key Key) PersistentMap
+
+	// Keys returns a slice containing all the keys in the map.
+	Keys() []Key
+
+	// Values returns a slice containing all the values in the map.
+	Values() []Value
+
+	// Pairs returns a slice containing all the key-value pairs in the map.
+	Pairs() []Pair
+
+	// Size returns the number of key-value pairs in the map.
+	Size() int
+
+	// Serialize behaves the same as Map.Serialize.
+	Serialize() map[string]interface{}
+
+	// Get a new Iterator for this map.
+	Iterator() Iterator
+
+	// Transient returns a TransientMap with the same contents as this map, which may be used to efficiently make
+	// many modifications, without affecting this map.
+	Transient() TransientMap
+
+	// Map returns a new Map with the same contents as this map.
+	Map() Map
+}
+
+// TransientMap is a mutable builder for a PersistentMap, which modifies it's own nodes in place, rather than copying
+// them for each modification. It is not safe for concurrent use.
+type TransientMap interface {
+	// Contains will return true if the key exists in the map.
+	Contains(key Key) bool
+
+	// Get will return the value if it exists in the map, or nil if it doesn't.
+	Get(key Key) Value
+
+	// Put will store value as key in the map, and will return any existing value, or nil.
+	Put(key Key, value Value) Value
+
+	// Remove removes any value that existed for key in the map, and will return it, or nil.
+	Remove(key Key) Value
+
+	// Size returns the number of key-value pairs in the map.
+	Size() int
+
+	// Persistent returns a PersistentMap with the current contents of this map. The transient map may continue to
+	// be used, and further modifications will not affect the returned map.
+	Persistent() PersistentMap
+}
+
+const (
+	hamtBits = 5
+	hamtMask = 1<<hamtBits - 1
+)
+
+// hamtEdit identifies the owner of mutable nodes, nodes may only be modified in place by the owner which created
+// them. It must not be zero sized, so that each allocation is distinct.
+type hamtEdit struct {
+	_ byte
+}
+
+// hamtNode is a node in a hash array mapped trie, where each level consumes hamtBits of the hash, with the populated
+// children stored compactly, indexed using the bitmap. Nodes beyond the last level of the hash (shift >= 32) are
+// collision nodes, which store pairs with identical hashes in a list.
+type hamtNode struct {
+	bitmap     uint32
+	children   []hamtChild
+	collisions []Pair
+	edit       *hamtEdit
+}
+
+// hamtChild is either a sub-node, or a single pair, along with it's hash.
+type hamtChild struct {
+	hash uint32
+	pair Pair
+	node *hamtNode
+}
+
+func hamtHash(key Key) uint32 {
+	h := hash64Of(key)
+	return uint32(h ^ h>>32)
+}
+
+// editable returns n if it is owned by edit, or a copy of it that is.
+func (n *hamtNode) editable(edit *hamtEdit) *hamtNode {
+	if nil == n {
+		return &hamtNode{edit: edit}
+	}
+	if n.edit == edit {
+		return n
+	}
+	c := &hamtNode{bitmap: n.bitmap, edit: edit}
+	if nil != n.children {
+		c.children = append(make([]hamtChild, 0, len(n.children)+1), n.children...)
+	}
+	if nil != n.collisions {
+		c.collisions = append(make([]Pair, 0, len(n.collisions)+1), n.collisions...)
+	}
+	return c
+}
+
+func (n *hamtNode) get(hash uint32, key Key) Pair {
+	for shift := uint(0); nil != n; shift += hamtBits {
+		if shift >= 32 {
+			for _, pair := range n.collisions {
+				if true == keysEqual(key, pair.Key()) {
+					return pair
+				}
+			}
+			return nil
+		}
+		bit := uint32(1) << (hash >> shift & hamtMask)
+		if 0 == n.bitmap&bit {
+			return nil
+		}
+		child := n.children[bits.OnesCount32(n.bitmap&(bit-1))]
+		if nil == child.node {
+			if child.hash == hash && true == keysEqual(key, child.pair.Key()) {
+				return child.pair
+			}
+			return nil
+		}
+		n = child.node
+	}
+	return nil
+}
+
+// assoc returns a node with pair stored, where old will be set to any replaced pair, and added will be set if the
+// key did not exist. The returned node will be n if no copy was necessary.
+func (n *hamtNode) assoc(edit *hamtEdit, hash uint32, shift uint, pair Pair, old *Pair, added *bool) *hamtNode {
+	if shift >= 32 {
+		if nil != n {
+			for i, p := range n.collisions {
+				if true == keysEqual(pair.Key(), p.Key()) {
+					*old = p
+					c := n.editable(edit)
+					c.collisions[i] = pair
+					return c
+				}
+			}
+		}
+		*added = true
+		c := n.editable(edit)
+		c.collisions = append(c.collisions, pair)
+		return c
+	}
+	bit := uint32(1) << (hash >> shift & hamtMask)
+	if nil == n || 0 == n.bitmap&bit {
+		*added = true
+		c := n.editable(edit)
+		pos := bits.OnesCount32(c.bitmap & (bit - 1))
+		c.children = append(c.children, hamtChild{})
+		copy(c.children[pos+1:], c.children[pos:])
+		c.children[pos] = hamtChild{hash: hash, pair: pair}
+		c.bitmap |= bit
+		return c
+	}
+	pos := bits.OnesCount32(n.bitmap & (bit - 1))
+	child := n.children[pos]
+	if nil != child.node {
+		node := child.node.assoc(edit, hash, shift+hamtBits, pair, old, added)
+		if node == child.node {
+			return n
+		}
+		c := n.editable(edit)
+		c.children[pos].node = node
+		return c
+	}
+	c := n.editable(edit)
+	if child.hash == hash && true == keysEqual(pair.Key(), child.pair.Key()) {
+		*old = child.pair
+		c.children[pos].pair = pair
+		return c
+	}
+	// split the existing pair into a new sub-node, along with the new pair
+	var discardOld Pair
+	var discardAdded bool
+	node := (*hamtNode)(nil).assoc(edit, child.hash, shift+hamtBits, child.pair, &discardOld, &discardAdded)
+	node = node.assoc(edit, hash, shift+hamtBits, pair, old, added)
+	c.children[pos] = hamtChild{node: node}
+	return c
+}
+
+// dissoc returns a node without key, or nil if it would be empty, where removed will be set to the removed pair.
+// The returned node will be n if the key did not exist.
+func (n *hamtNode) dissoc(edit *hamtEdit, hash uint32, shift uint, key Key, removed *Pair) *hamtNode {
+	if shift >= 32 {
+		for i, p := range n.collisions {
+			if true == keysEqual(key, p.Key()) {
+				*removed = p
+				if 1 == len(n.collisions) {
+					return nil
+				}
+				c := n.editable(edit)
+				copy(c.collisions[i:], c.collisions[i+1:])
+				c.collisions[len(c.collisions)-1] = nil
+				c.collisions = c.collisions[:len(c.collisions)-1]
+				return c
+			}
+		}
+		return n
+	}
+	bit := uint32(1) << (hash >> shift & hamtMask)
+	if 0 == n.bitmap&bit {
+		return n
+	}
+	pos := bits.OnesCount32(n.bitmap & (bit - 1))
+	child := n.children[pos]
+	if nil != child.node {
+		node := child.node.dissoc(edit, hash, shift+hamtBits, key, removed)
+		if node == child.node {
+			return n
+		}
+		if nil != node {
+			c := n.editable(edit)
+			c.children[pos] = node.collapse(hash, shift+hamtBits)
+			return c
+		}
+	} else if child.hash != hash || false == keysEqual(key, child.pair.Key()) {
+		return n
+	} else {
+		*removed = child.pair
+	}
+	if 1 == len(n.children) {
+		return nil
+	}
+	c := n.editable(edit)
+	copy(c.children[pos:], c.children[pos+1:])
+	c.children[len(c.children)-1] = hamtChild{}
+	c.children = c.children[:len(c.children)-1]
+	c.bitmap &^= bit
+	return c
+}
+
+// collapse converts a node that only contains a single pair into a pair child, for a node at the given shift, where
+// hash is the hash of any key in the node (for collision nodes).
+func (n *hamtNode) collapse(hash uint32, shift uint) hamtChild {
+	if shift >= 32 {
+		if 1 == len(n.collisions) {
+			return hamtChild{hash: hash, pair: n.collisions[0]}
+		}
+	} else if 1 == len(n.children) && nil == n.children[0].node {
+		return n.children[0]
+	}
+	return hamtChild{node: n}
+}
+
+// walk calls fn with each pair, stopping early if fn returns false.
+func (n *hamtNode) walk(fn func(pair Pair) bool) bool {
+	if nil == n {
+		return true
+	}
+	for _, pair := range n.collisions {
+		if false == fn(pair) {
+			return false
+		}
+	}
+	for _, child := range n.children {
+		if nil != child.node {
+			if false == child.node.walk(fn) {
+				return false
+			}
+		} else if false == fn(child.pair) {
+			return false
+		}
+	}
+	return true
+}
+
+// persistentMap implements PersistentMap using a hash array mapped trie, where each modification is performed as
+// if by a transient map that is immediately discarded.
+type persistentMap struct {
+	root *hamtNode
+	size int
+}
+
+func (m *persistentMap) Contains(key Key) bool {
+	return nil != m.root.get(hamtHash(key), key)
+}
+
+func (m *persistentMap) Get(key Key) Value {
+	if pair := m.root.get(hamtHash(key), key); nil != pair {
+		return pair.Value()
+	}
+	return nil
+}
+
+func (m *persistentMap) With(key Key, value Value) PersistentMap {
+	t := m.Transient()
+	t.Put(key, value)
+	return t.Persistent()
+}
+
+func (m *persistentMap) Without(key Key) PersistentMap {
+	if false == m.Contains(key) {
+		return m
+	}
+	t := m.Transient()
+	t.Remove(key)
+	return t.Persistent()
+}
+
+func (m *persistentMap) Keys() []Key {
+	keys := make([]Key, 0, m.size)
+	m.root.walk(func(pair Pair) bool {
+		keys = append(keys, pair.Key())
+		return true
+	})
+	return keys
+}
+
+func (m *persistentMap) Values() []Value {
+	values := make([]Value, 0, m.size)
+	m.root.walk(func(pair Pair) bool {
+		values = append(values, pair.Value())
+		return true
+	})
+	return values
+}
+
+func (m *persistentMap) Pairs() []Pair {
+	pairs := make([]Pair, 0, m.size)
+	m.root.walk(func(pair Pair) bool {
+		pairs = append(pairs, pair)
+		return true
+	})
+	return pairs
+}
+
+func (m *persistentMap) Size() int {
+	return m.size
+}
+
+func (m *persistentMap) Serialize() map[string]interface{} {
+	serialized := make(map[string]interface{})
+	m.root.walk(func(pair Pair) bool {
+		serialized[serializeKey(pair.Key())] = pair.Value()
+		return true
+	})
+	return serialized
+}
+
+// serializeKey converts key to a string for Map.Serialize, using fmt.Stringer if it is implemented.
+func serializeKey(key Key) string {
+	if kStringer, ok := key.(fmt.Stringer); true == ok {
+		return kStringer.String()
+	}
+	return fmt.Sprintf("%v", key)
+}
+
+func (m *persistentMap) Iterator() Iterator {
+	return newSliceIterator(m.Pairs())
+}
+
+func (m *persistentMap) Transient() TransientMap {
+	return &transientMap{m.root, m.size, &hamtEdit{}}
+}
+
+func (m *persistentMap) Map() Map {
+	result := NewMap()
+	m.root.walk(func(pair Pair) bool {
+		result.Put(pair.Key(), pair.Value())
+		return true
+	})
+	return result
+}
+
+// transientMap implements TransientMap, sharing nodes with the persistent map it was created from, until they are
+// modified.
+type transientMap struct {
+	root *hamtNode
+	size int
+	edit *hamtEdit
+}
+
+func (t *transientMap) Contains(key Key) bool {
+	return nil != t.root.get(hamtHash(key), key)
+}
+
+func (t *transientMap) Get(key Key) Value {
+	if pair := t.root.get(hamtHash(key), key); nil != pair {
+		return pair.Value()
+	}
+	return nil
+}
+
+func (t *transientMap) Put(key Key, value Value) Value {
+	var old Pair
+	added := false
+	t.root = t.root.assoc(t.edit, hamtHash(key), 0, NewPair(key, value), &old, &added)
+	if true == added {
+		t.size++
+	}
+	if nil == old {
+		return nil
+	}
+	return old.Value()
+}
+
+func (t *transientMap) Remove(key Key) Value {
+	if nil == t.root {
+		return nil
+	}
+	var removed Pair
+	t.root = t.root.dissoc(t.edit, hamtHash(key), 0, key, &removed)
+	if nil == removed {
+		return nil
+	}
+	t.size--
+	return removed.Value()
+}
+
+func (t *transientMap) Size() int {
+	return t.size
+}
+
+func (t *transientMap) Persistent() PersistentMap {
+	// take ownership of the nodes away from this transient, so they may not be modified any further
+	t.edit = &hamtEdit{}
+	return &persistentMap{t.root, t.size}
+}
+
+// NewPersistentMap returns an empty PersistentMap.
+func NewPersistentMap() PersistentMap {
+	return &persistentMap{}
+}
+
+// PersistentMapOf returns a PersistentMap with the same contents as m.
+func PersistentMapOf(m Map) PersistentMap {
+	t := NewPersistentMap().Transient()
+	for it := m.Iterator(); true == it.Next(); {
+		t.Put(it.Key(), it.Value())
+	}
+	return t.Persistent()
+}
@@ -0,0 +1,216 @@
+/*
+   Copyright 2017 Joseph Cumines
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+
+ */
+
+package simhash
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func TestPersistentMap_With(t *testing.T) {
+	empty := NewPersistentMap()
+	one := empty.With(testKeyInt(1), 1)
+	two := one.With(testKeyInt(2), 2)
+	replaced := two.With(testKeyInt(1), 11)
+	if 0 != empty.Size() || 1 != one.Size() || 2 != two.Size() || 2 != replaced.Size() {
+		t.Fatal()
+	}
+	if true == empty.Contains(testKeyInt(1)) || 1 != one.Get(testKeyInt(1)).(int) || nil != one.Get(testKeyInt(2)) ||
+		1 != two.Get(testKeyInt(1)).(int) || 11 != replaced.Get(testKeyInt(1)).(int) || 2 != replaced.Get(testKeyInt(2)).(int) {
+		t.Fatal()
+	}
+	withNil := replaced.With(nil, 0)
+	if 3 != withNil.Size() || true != withNil.Contains(nil) || 0 != withNil.Get(nil).(int) || true == replaced.Contains(nil) {
+		t.Fatal()
+	}
+}
+
+func TestPersistentMap_Without(t *testing.T) {
+	m := NewPersistentMap().With(testKeyInt(1), 1).With(testKeyInt(2), 2)
+	if m.Without(testKeyInt(3)) != m {
+		t.Fatal()
+	}
+	without := m.Without(testKeyInt(1))
+	if 1 != without.Size() || true == without.Contains(testKeyInt(1)) || 2 != m.Size() || true != m.Contains(testKeyInt(1)) {
+		t.Fatal()
+	}
+	if 0 != without.Without(testKeyInt(2)).Size() || 0 != NewPersistentMap().Without(nil).Size() {
+		t.Fatal()
+	}
+}
+
+func TestPersistentMap_collisions(t *testing.T) {
+	m := NewPersistentMap()
+	for i := 0; i < 10; i++ {
+		m = m.With(testKeyStruct{7, i}, i)
+	}
+	if 10 != m.Size() {
+		t.Fatal()
+	}
+	for i := 0; i < 10; i++ {
+		if i != m.Get(testKeyStruct{7, i}).(int) {
+			t.Fatal(i)
+		}
+	}
+	m = m.With(testKeyStruct{7, 3}, 33)
+	if 10 != m.Size() || 33 != m.Get(testKeyStruct{7, 3}).(int) {
+		t.Fatal()
+	}
+	for i := 0; i < 10; i++ {
+		m = m.Without(testKeyStruct{7, i})
+		if 9-i != m.Size() || true == m.Contains(testKeyStruct{7, i}) {
+			t.Fatal(i)
+		}
+	}
+}
+
+func TestPersistentMap_random(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	type snapshot struct {
+		m     PersistentMap
+		model map[testKeyStruct]int
+	}
+	snapshots := make([]snapshot, 0)
+	m := NewPersistentMap()
+	model := make(map[testKeyStruct]int)
+	for x := 0; x < 3000; x++ {
+		// a small range of hashes, including some that differ only in the high bits
+		h := r.Intn(50)
+		if 0 == r.Intn(5) {
+			h <<= 30
+		}
+		k := testKeyStruct{h, r.Intn(4)}
+		if 0 == r.Intn(3) {
+			m = m.Without(k)
+			delete(model, k)
+		} else {
+			m = m.With(k, x)
+			model[k] = x
+		}
+		if 0 == x%100 {
+			copied := make(map[testKeyStruct]int)
+			for k, v := range model {
+				copied[k] = v
+			}
+			snapshots = append(snapshots, snapshot{m, copied})
+		}
+	}
+	snapshots = append(snapshots, snapshot{m, model})
+	for _, s := range snapshots {
+		if len(s.model) != s.m.Size() || len(s.model) != len(s.m.Pairs()) {
+			t.Fatal(len(s.model), s.m.Size())
+		}
+		for k, v := range s.model {
+			if v != s.m.Get(k).(int) {
+				t.Fatal(k)
+			}
+		}
+		for _, pair := range s.m.Pairs() {
+			if v, ok := s.model[pair.Key().(testKeyStruct)]; false == ok || v != pair.Value().(int) {
+				t.Fatal(pair)
+			}
+		}
+	}
+}
+
+func TestTransientMap(t *testing.T) {
+	base := NewPersistentMap().With(testKeyInt(1), 1)
+	tm := base.Transient()
+	if nil != tm.Put(testKeyInt(2), 2) || 1 != tm.Put(testKeyInt(1), 11).(int) || 2 != tm.Size() {
+		t.Fatal()
+	}
+	if true != tm.Contains(testKeyInt(2)) || 11 != tm.Get(testKeyInt(1)).(int) || 1 != base.Get(testKeyInt(1)).(int) || 1 != base.Size() {
+		t.Fatal()
+	}
+	first := tm.Persistent()
+	if 2 != tm.Remove(testKeyInt(2)).(int) || nil != tm.Remove(testKeyInt(2)) || 1 != tm.Size() {
+		t.Fatal()
+	}
+	for i := 3; i < 100; i++ {
+		tm.Put(testKeyInt(i), i)
+	}
+	second := tm.Persistent()
+	if 2 != first.Size() || 2 != first.Get(testKeyInt(2)).(int) || 11 != first.Get(testKeyInt(1)).(int) || 98 != second.Size() {
+		t.Fatal()
+	}
+	if nil != NewPersistentMap().Transient().Remove(testKeyInt(1)) {
+		t.Fatal()
+	}
+}
+
+func TestPersistentMap_views(t *testing.T) {
+	m := NewPersistentMap()
+	for x := 1; x <= 3; x++ {
+		for y := 1; y <= 3; y++ {
+			i := x*10 + y
+			m = m.With(testKeyStruct{x, i}, i)
+		}
+	}
+	values := make([]int, 0)
+	for _, v := range m.Values() {
+		values = append(values, v.(int))
+	}
+	sort.Ints(values)
+	if false == equalInts([]int{11, 12, 13, 21, 22, 23, 31, 32, 33}, values) || 9 != len(m.Keys()) {
+		t.Fatal(values)
+	}
+	if s := m.Serialize(); 9 != len(s) || 22 != s["22"].(int) {
+		t.Fatal(s)
+	}
+	count := 0
+	for it := m.Iterator(); true == it.Next(); {
+		if it.Key().(testKeyStruct).val != it.Value().(int) {
+			t.Fatal()
+		}
+		count++
+	}
+	if 9 != count {
+		t.Fatal()
+	}
+}
+
+func TestPersistentMap_adapters(t *testing.T) {
+	source := genTestStructureHashMap()
+	p := PersistentMapOf(source)
+	if 10 != p.Size() || 0 != p.Get(nil).(int) || 23 != p.Get(testKeyStruct{2, 23}).(int) {
+		t.Fatal()
+	}
+	m := p.Map()
+	m.Put(testKeyInt(99), 99)
+	if 11 != m.Size() || 10 != p.Size() || 23 != m.Get(testKeyStruct{2, 23}).(int) {
+		t.Fatal()
+	}
+}
+
+func TestPersistentMap_structuralSharing(t *testing.T) {
+	// keys 1 and 33 share the first level, forming a sub-node, which must be shared after adding key 2
+	m := NewPersistentMap().With(testKeyInt(1), 1).With(testKeyInt(33), 33).(*persistentMap)
+	if 1 != len(m.root.children) || nil == m.root.children[0].node {
+		t.Fatal()
+	}
+	m2 := m.With(testKeyInt(2), 2).(*persistentMap)
+	if 2 != len(m2.root.children) || m2.root == m.root || m2.root.children[0].node != m.root.children[0].node {
+		t.Fatal()
+	}
+	// and removing a key collapses the sub-node
+	m3 := m2.Without(testKeyInt(33)).(*persistentMap)
+	if 2 != len(m3.root.children) || nil != m3.root.children[0].node || 1 != m3.root.children[0].pair.Value().(int) {
+		t.Fatal()
+	}
+}
@@ -0,0 +1,318 @@
+/*
+   Copyright 2017 Joseph Cumines
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+
+ */
+
+package simhash
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+
+	"github.com/joeycumines/go-hashmap/simhash/internal/hashbits"
+)
+
+const (
+	// reflectCycleHash is used in place of the hash of a pointer, map or slice that is already being hashed.
+	reflectCycleHash = 0x5851f42d4c957f2d
+)
+
+var keyType = reflect.TypeOf((*Key)(nil)).Elem()
+
+// reflectKey is a Key for an arbitrary value, see ReflectKey. The hash is computed once, when it is created.
+type reflectKey struct {
+	v    interface{}
+	hash uint64
+}
+
+// reflectVisit identifies a pointer, map or slice that is being compared, or hashed, to detect cycles.
+type reflectVisit struct {
+	a, b uintptr
+	t    reflect.Type
+}
+
+// ReflectKey returns a Key for v, which may be any value, e.g. a struct that doesn't implement Key, by using
+// reflection to compute a structural hash, and compare it with deep equality, following the same rules as
+// reflect.DeepEqual, with the following exceptions:
+//
+//   - values that implement Key are compared using Equals, and hashed using Hash, or Hash64, see Key64, unless they
+//     are nil, or unexported struct fields, which are not accessible
+//   - floats are equal if they have the same representation, with every NaN being equal, and 0 and -0 being
+//     different, the same as Java's Double.equals, so that keys are always equal to themselves, including map keys
+//   - funcs are equal if they are both nil, or have the same code pointer, so closures created by the same function
+//     literal are equal, regardless of the variables they capture
+//
+// Cycles are supported, though cyclic values are only equal if their cycles have the same length, as keys with
+// different hashes are never equal. The value must not be modified after it is wrapped. The returned key also
+// implements Key64, and fmt.Stringer, which formats v using fmt.Sprint. ReflectKey(nil) returns nil, and ReflectKey of
+// another ReflectKey returns it unchanged.
+// It is intended for prototyping, as it will be significantly slower than a Key implemented for a specific type.
+func ReflectKey(v interface{}) Key {
+	switch v := v.(type) {
+	case nil:
+		return nil
+	case reflectKey:
+		return v
+	}
+	return reflectKey{v, reflectHash(reflect.ValueOf(v), make(map[reflectVisit]bool))}
+}
+
+func (k reflectKey) Hash() int {
+	return hashOf(k)
+}
+
+func (k reflectKey) Hash64() uint64 {
+	return k.hash
+}
+
+func (k reflectKey) Equals(other interface{}) bool {
+	o, ok := other.(reflectKey)
+	return ok && k.hash == o.hash && reflectEqual(reflect.ValueOf(k.v), reflect.ValueOf(o.v), make(map[reflectVisit]bool))
+}
+
+func (k reflectKey) String() string {
+	return fmt.Sprint(k.v)
+}
+
+// asKey returns v as a Key, if it implements Key and is accessible, and is not nil. Pointers to keys with value
+// receivers are not keys, as they would be passed to an Equals expecting the element type, and are instead compared
+// using their elements.
+func asKey(v reflect.Value) (Key, bool) {
+	if false == v.Type().Implements(keyType) || false == v.CanInterface() {
+		return nil, false
+	}
+	if reflect.Ptr == v.Kind() && true == v.Type().Elem().Implements(keyType) {
+		return nil, false
+	}
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan:
+		if true == v.IsNil() {
+			return nil, false
+		}
+	}
+	return v.Interface().(Key), true
+}
+
+func reflectFloat(f float64) uint64 {
+	if f != f {
+		return 0x7ff8000000000000
+	}
+	return math.Float64bits(f)
+}
+
+// reflectHash returns the structural hash of v, where visiting tracks the pointers, maps and slices that are
+// currently being hashed.
+func reflectHash(v reflect.Value, visiting map[reflectVisit]bool) uint64 {
+	if false == v.IsValid() {
+		return hashbits.Nil
+	}
+	if k, ok := asKey(v); true == ok {
+		return hash64Of(k)
+	}
+	h := uint64(hashbits.Offset)
+	add := func(x uint64) {
+		h = hashbits.Mix(h) ^ x
+	}
+	switch v.Kind() {
+	case reflect.Bool:
+		if true == v.Bool() {
+			add(1)
+		} else {
+			add(0)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		add(uint64(v.Int()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		add(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		add(reflectFloat(v.Float()))
+	case reflect.Complex64, reflect.Complex128:
+		add(reflectFloat(real(v.Complex())))
+		add(reflectFloat(imag(v.Complex())))
+	case reflect.String:
+		add(hashbits.String(v.String()))
+	case reflect.Ptr, reflect.Interface:
+		if true == v.IsNil() {
+			return hashbits.Nil
+		}
+		if reflect.Ptr == v.Kind() {
+			visit := reflectVisit{v.Pointer(), 0, v.Type()}
+			if true == visiting[visit] {
+				return reflectCycleHash
+			}
+			visiting[visit] = true
+			defer delete(visiting, visit)
+		}
+		add(reflectHash(v.Elem(), visiting))
+	case reflect.Slice, reflect.Map:
+		if true == v.IsNil() {
+			return hashbits.Nil
+		}
+		visit := reflectVisit{v.Pointer(), uintptr(v.Len()), v.Type()}
+		if true == visiting[visit] {
+			return reflectCycleHash
+		}
+		visiting[visit] = true
+		defer delete(visiting, visit)
+		add(uint64(v.Len()))
+		if reflect.Slice == v.Kind() {
+			for i := 0; i < v.Len(); i++ {
+				add(reflectHash(v.Index(i), visiting))
+			}
+			break
+		}
+		// the order of a map is random, so the entries are hashed individually, then summed
+		var sum uint64
+		for it := v.MapRange(); it.Next(); {
+			sum += hashbits.Mix(reflectHash(it.Key(), visiting)) ^ reflectHash(it.Value(), visiting)
+		}
+		add(sum)
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			add(reflectHash(v.Index(i), visiting))
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			add(reflectHash(v.Field(i), visiting))
+		}
+	case reflect.Func:
+		if true == v.IsNil() {
+			return hashbits.Nil
+		}
+		add(uint64(v.Pointer()))
+	case reflect.Chan, reflect.UnsafePointer:
+		add(uint64(v.Pointer()))
+	}
+	return hashbits.Mix(h)
+}
+
+// reflectEqual compares a and b, the same as reflect.DeepEqual, with the exceptions documented by ReflectKey, where
+// visited tracks the pointers, maps and slices that have already been compared, which are assumed to be equal.
+func reflectEqual(a, b reflect.Value, visited map[reflectVisit]bool) bool {
+	if false == a.IsValid() || false == b.IsValid() {
+		return a.IsValid() == b.IsValid()
+	}
+	if a.Type() != b.Type() {
+		return false
+	}
+	if k, ok := asKey(a); true == ok {
+		o, ok := asKey(b)
+		return ok && k.Equals(o)
+	}
+	switch a.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice:
+		if a.IsNil() || b.IsNil() {
+			return a.IsNil() == b.IsNil()
+		}
+		visit := reflectVisit{a.Pointer(), b.Pointer(), a.Type()}
+		if reflect.Ptr != a.Kind() && a.Len() != b.Len() {
+			return false
+		}
+		if true == visited[visit] {
+			return true
+		}
+		visited[visit] = true
+	}
+	switch a.Kind() {
+	case reflect.Bool:
+		return a.Bool() == b.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return a.Int() == b.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return a.Uint() == b.Uint()
+	case reflect.Float32, reflect.Float64:
+		return reflectFloat(a.Float()) == reflectFloat(b.Float())
+	case reflect.Complex64, reflect.Complex128:
+		return reflectFloat(real(a.Complex())) == reflectFloat(real(b.Complex())) &&
+			reflectFloat(imag(a.Complex())) == reflectFloat(imag(b.Complex()))
+	case reflect.String:
+		return a.String() == b.String()
+	case reflect.Ptr, reflect.Interface:
+		if a.IsNil() || b.IsNil() {
+			return a.IsNil() == b.IsNil()
+		}
+		return reflectEqual(a.Elem(), b.Elem(), visited)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < a.Len(); i++ {
+			if false == reflectEqual(a.Index(i), b.Index(i), visited) {
+				return false
+			}
+		}
+		return true
+	case reflect.Map:
+		return reflectMapEqual(a, b, visited)
+	case reflect.Struct:
+		for i := 0; i < a.NumField(); i++ {
+			if false == reflectEqual(a.Field(i), b.Field(i), visited) {
+				return false
+			}
+		}
+		return true
+	case reflect.Func:
+		if a.IsNil() || b.IsNil() {
+			return a.IsNil() == b.IsNil()
+		}
+		return a.Pointer() == b.Pointer()
+	case reflect.Chan, reflect.UnsafePointer:
+		return a.Pointer() == b.Pointer()
+	}
+	return false
+}
+
+// reflectMapEqual compares two maps of the same type and length, for reflectEqual. Entries are matched by looking up
+// each key of a in b, except for keys that can't be found, even in their own map, e.g. a NaN, which are instead
+// matched against the remaining entries of b, by iterating, so that maps containing them are still equal to
+// themselves.
+func reflectMapEqual(a, b reflect.Value, visited map[reflectVisit]bool) bool {
+	var unmatched []reflect.Value
+	for it := a.MapRange(); it.Next(); {
+		bv := b.MapIndex(it.Key())
+		if false == bv.IsValid() {
+			unmatched = append(unmatched, it.Key(), it.Value())
+			continue
+		}
+		if false == reflectEqual(it.Value(), bv, visited) {
+			return false
+		}
+	}
+	if 0 == len(unmatched) {
+		return true
+	}
+	var candidates []reflect.Value
+	for it := b.MapRange(); it.Next(); {
+		if false == a.MapIndex(it.Key()).IsValid() {
+			candidates = append(candidates, it.Key(), it.Value())
+		}
+	}
+	if len(candidates) != len(unmatched) {
+		return false
+	}
+	for i := 0; i < len(unmatched); i += 2 {
+		found := false
+		for j := 0; j < len(candidates); j += 2 {
+			if true == reflectEqual(unmatched[i], candidates[j], visited) &&
+				true == reflectEqual(unmatched[i+1], candidates[j+1], visited) {
+				candidates = append(candidates[:j], candidates[j+2:]...)
+				found = true
+				break
+			}
+		}
+		if false == found {
+			return false
+		}
+	}
+	return true
+}
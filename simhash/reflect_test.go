@@ -0,0 +1,254 @@
+/*
+   Copyright 2017 Joseph Cumines
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+
+ */
+
+package simhash
+
+import (
+	"math"
+	"testing"
+)
+
+type testReflectStruct struct {
+	Name     string
+	id       int
+	Score    float64
+	Tags     []string
+	Attrs    map[string]int
+	Parent   *testReflectStruct
+	Key      Key
+	key      Key
+	Any      interface{}
+	Callback func()
+}
+
+type testReflectNode struct {
+	Value int
+	Next  *testReflectNode
+}
+
+func newTestReflectStruct() testReflectStruct {
+	return testReflectStruct{
+		Name:   "a",
+		id:     1,
+		Score:  1.5,
+		Tags:   []string{"x", "y"},
+		Attrs:  map[string]int{"a": 1, "b": 2},
+		Parent: &testReflectStruct{Name: "parent"},
+		Key:    testKeyStruct{1, 2},
+		key:    testKeyInt(3),
+		Any:    []int{1},
+	}
+}
+
+func assertReflectEqual(t *testing.T, a, b interface{}) {
+	t.Helper()
+	ka, kb := ReflectKey(a), ReflectKey(b)
+	if false == ka.Equals(kb) || false == kb.Equals(ka) || ka.Hash() != kb.Hash() {
+		t.Fatalf("expected equal: %#v %#v", a, b)
+	}
+}
+
+func assertReflectNotEqual(t *testing.T, a, b interface{}) {
+	t.Helper()
+	ka, kb := ReflectKey(a), ReflectKey(b)
+	if true == ka.Equals(kb) || true == kb.Equals(ka) {
+		t.Fatalf("expected not equal: %#v %#v", a, b)
+	}
+}
+
+func TestReflectKey(t *testing.T) {
+	if nil != ReflectKey(nil) {
+		t.Fatal()
+	}
+	k := ReflectKey(5)
+	if k != ReflectKey(k) || "5" != k.(interface{ String() string }).String() {
+		t.Fatal()
+	}
+	if _, ok := k.(Key64); false == ok || k.Hash() != hashOf(k) {
+		t.Fatal()
+	}
+	if true == k.Equals(5) || true == k.Equals(nil) || true == k.Equals(testKeyInt(5)) {
+		t.Fatal()
+	}
+}
+
+func TestReflectKey_primitives(t *testing.T) {
+	assertReflectEqual(t, 1, 1)
+	assertReflectEqual(t, "a", "a")
+	assertReflectEqual(t, true, true)
+	assertReflectEqual(t, uint8(3), uint8(3))
+	assertReflectEqual(t, math.NaN(), math.NaN())
+	assertReflectEqual(t, complex(1, math.NaN()), complex(1, math.NaN()))
+	assertReflectNotEqual(t, 1, 2)
+	assertReflectNotEqual(t, 1, int64(1))
+	assertReflectNotEqual(t, "a", "b")
+	assertReflectNotEqual(t, true, false)
+	assertReflectNotEqual(t, 0.0, math.Copysign(0, -1))
+	assertReflectNotEqual(t, complex(1, 2), complex(1, 3))
+}
+
+func TestReflectKey_struct(t *testing.T) {
+	assertReflectEqual(t, newTestReflectStruct(), newTestReflectStruct())
+	a := newTestReflectStruct()
+	assertReflectEqual(t, &a, &a)
+	for i, modify := range []func(s *testReflectStruct){
+		func(s *testReflectStruct) { s.Name = "b" },
+		func(s *testReflectStruct) { s.id = 2 },
+		func(s *testReflectStruct) { s.Score = math.NaN() },
+		func(s *testReflectStruct) { s.Tags = []string{"y", "x"} },
+		func(s *testReflectStruct) { s.Tags = []string{} },
+		func(s *testReflectStruct) { s.Tags = nil },
+		func(s *testReflectStruct) { s.Attrs["c"] = 3 },
+		func(s *testReflectStruct) { s.Attrs = nil },
+		func(s *testReflectStruct) { s.Parent = nil },
+		func(s *testReflectStruct) { s.Parent.Name = "other" },
+		func(s *testReflectStruct) { s.Key = nil },
+		func(s *testReflectStruct) { s.Key = testKeyStruct{2, 2} },
+		func(s *testReflectStruct) { s.key = testKeyInt(4) },
+		func(s *testReflectStruct) { s.Any = []int{2} },
+		func(s *testReflectStruct) { s.Callback = func() {} },
+	} {
+		b := newTestReflectStruct()
+		modify(&b)
+		if true == ReflectKey(a).Equals(ReflectKey(b)) || true == ReflectKey(b).Equals(ReflectKey(a)) {
+			t.Errorf("%d: expected not equal", i)
+		}
+		if ReflectKey(a).Hash() == ReflectKey(b).Hash() {
+			t.Errorf("%d: expected a different hash", i)
+		}
+	}
+	// types are compared, but aren't part of the hash
+	b := newTestReflectStruct()
+	b.Any = []int64{1}
+	assertReflectNotEqual(t, a, b)
+	// functions are equal if they have the same code pointer
+	f := func() {}
+	b, c := newTestReflectStruct(), newTestReflectStruct()
+	b.Callback, c.Callback = f, f
+	assertReflectEqual(t, b, b)
+	assertReflectEqual(t, b, c)
+	c.Callback = func() {}
+	assertReflectNotEqual(t, b, c)
+	c.Callback = nil
+	assertReflectNotEqual(t, b, c)
+}
+
+func TestReflectKey_nanMapKeys(t *testing.T) {
+	nan := math.NaN()
+	a := map[float64]int{nan: 1, nan: 2, 1: 3}
+	if 3 != len(a) {
+		t.Fatal(len(a))
+	}
+	assertReflectEqual(t, a, a)
+	assertReflectEqual(t, a, map[float64]int{1: 3, nan: 2, nan: 1})
+	assertReflectNotEqual(t, a, map[float64]int{nan: 1, nan: 1, 1: 3})
+	assertReflectNotEqual(t, a, map[float64]int{nan: 1, 2: 2, 1: 3})
+	assertReflectNotEqual(t, a, map[float64]int{nan: 1, 1: 3})
+	type S struct {
+		M map[[1]float64]string
+	}
+	s := S{map[[1]float64]string{{nan}: "a", {0}: "b"}}
+	k := ReflectKey(s)
+	m := NewMap()
+	m.Put(k, 1)
+	if 1 != m.Get(k) || 1 != m.Get(ReflectKey(S{map[[1]float64]string{{nan}: "a", {0}: "b"}})) || 1 != m.Remove(k) {
+		t.Fatal()
+	}
+}
+
+func TestReflectKey_nestedKeys(t *testing.T) {
+	// keys are compared using Equals, where testKeyStruct has a hash independent of it's value
+	a, b := newTestReflectStruct(), newTestReflectStruct()
+	a.Key, b.Key = testKeyCollision(1), testKeyCollision(1)
+	assertReflectEqual(t, a, b)
+	if ReflectKey(a).Hash() != ReflectKey(b).Hash() {
+		t.Fatal()
+	}
+	assertReflectEqual(t, testKeyInt(5), testKeyInt(5))
+	assertReflectNotEqual(t, testKeyInt(5), testKeyInt(6))
+	// as are keys nested in interface{}
+	a.Any, b.Any = testKeyInt(1), testKeyInt(1)
+	assertReflectEqual(t, a, b)
+	b.Any = testKeyInt(2)
+	assertReflectNotEqual(t, a, b)
+	// nil pointers that implement Key are not called
+	assertReflectEqual(t, (*testKeyPtr)(nil), (*testKeyPtr)(nil))
+	assertReflectNotEqual(t, (*testKeyPtr)(nil), &testKeyPtr{})
+}
+
+func TestReflectKey_pointerToKey(t *testing.T) {
+	// testKeyInt has value receivers, so a pointer to it must be compared using the element
+	type S struct {
+		P *testKeyInt
+	}
+	x, y, z := testKeyInt(1), testKeyInt(1), testKeyInt(2)
+	k := ReflectKey(S{P: &x})
+	if false == k.Equals(k) {
+		t.Fatal("not reflexive")
+	}
+	assertReflectEqual(t, S{P: &x}, S{P: &y})
+	assertReflectNotEqual(t, S{P: &x}, S{P: &z})
+	assertReflectNotEqual(t, S{P: &x}, S{})
+	assertReflectEqual(t, S{}, S{})
+	assertReflectEqual(t, &x, &y)
+	assertReflectNotEqual(t, &x, &z)
+	m := NewMap()
+	m.Put(k, 1)
+	if 1 != m.Get(k) || 1 != m.Get(ReflectKey(S{P: &y})) {
+		t.Fatal()
+	}
+}
+
+func TestReflectKey_cycles(t *testing.T) {
+	newCycle := func(n int) *testReflectNode {
+		head := &testReflectNode{Value: 0}
+		tail := head
+		for i := 1; i < n; i++ {
+			tail.Next = &testReflectNode{Value: 0}
+			tail = tail.Next
+		}
+		tail.Next = head
+		return head
+	}
+	assertReflectEqual(t, newCycle(1), newCycle(1))
+	assertReflectEqual(t, newCycle(3), newCycle(3))
+	assertReflectNotEqual(t, newCycle(1), newCycle(2))
+	s := []interface{}{1, nil}
+	s[1] = s
+	o := []interface{}{1, nil}
+	o[1] = o
+	assertReflectEqual(t, s, o)
+	m := map[string]interface{}{}
+	m["self"] = m
+	n := map[string]interface{}{}
+	n["self"] = n
+	assertReflectEqual(t, m, n)
+}
+
+func TestReflectKey_map(t *testing.T) {
+	m := NewMap()
+	for i := 0; i < 10; i++ {
+		s := newTestReflectStruct()
+		s.id = i
+		m.Put(ReflectKey(s), i)
+	}
+	s := newTestReflectStruct()
+	s.id = 5
+	if 10 != m.Size() || 5 != m.Get(ReflectKey(s)).(int) || nil != m.Get(ReflectKey(&s)) {
+		t.Fatal(m.Size())
+	}
+}
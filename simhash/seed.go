@@ -0,0 +1,71 @@
+/*
+   Copyright 2017 Joseph Cumines
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+
+ */
+
+package simhash
+
+import (
+	"fmt"
+
+	"github.com/joeycumines/go-hashmap/simhash/internal/hashbits"
+)
+
+// BucketLimitError describes a bucket which grew beyond the limit configured using WithBucketLimit.
+type BucketLimitError struct {
+	// Key is the key which caused the bucket to grow beyond the limit.
+	Key Key
+
+	// Hash is the hash shared by every key in the bucket, as returned by Key.Hash, or Key64.Hash64 converted to an
+	// int.
+	Hash int
+
+	// Size is the number of keys in the bucket, including Key.
+	Size int
+
+	// Limit is the configured limit.
+	Limit int
+}
+
+func (e *BucketLimitError) Error() string {
+	return fmt.Sprintf(
+		"%d keys share the hash %d, exceeding the bucket limit of %d, which may indicate hash flooding",
+		e.Size,
+		e.Hash,
+		e.Limit,
+	)
+}
+
+// hash returns the bucket of key, which is it's hash, mixed with the seed of the map, if it has one.
+func (m *hashMap) hash(key Key) int {
+	h := hashOf(key)
+	if true == m.seeded {
+		h = int(hashbits.Mix(uint64(h) ^ m.seed))
+	}
+	return h
+}
+
+// checkBucketLimit calls the bucket limit callback if the bucket for hash h has just grown beyond the limit.
+func (m *hashMap) checkBucketLimit(h int, key Key) {
+	if 0 == m.bucketLimit || len(m.m[h]) != m.bucketLimit+1 {
+		return
+	}
+	m.onBucketLimit(&BucketLimitError{
+		Key:   key,
+		Hash:  hashOf(key),
+		Size:  len(m.m[h]),
+		Limit: m.bucketLimit,
+	})
+}
@@ -0,0 +1,169 @@
+/*
+   Copyright 2017 Joseph Cumines
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+
+ */
+
+package simhash
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNewMap_WithHashSeed(t *testing.T) {
+	a := NewMap(WithHashSeed(1)).(*hashMap)
+	b := NewMap(WithHashSeed(2)).(*hashMap)
+	if true != a.seeded || 1 != a.seed || a.hash(testKeyInt(5)) == b.hash(testKeyInt(5)) {
+		t.Fatal()
+	}
+	if a.hash(testKeyInt(5)) != NewMap(WithHashSeed(1)).(*hashMap).hash(testKeyInt(5)) {
+		t.Fatal()
+	}
+	if 5 != NewMap(WithoutHashSeed()).(*hashMap).hash(testKeyInt(5)) {
+		t.Fatal()
+	}
+	// a random seed is used by default, unless disabled, and the last option wins
+	if m := NewMap().(*hashMap); true != m.seeded {
+		t.Fatal()
+	}
+	if m := NewMap(WithoutHashSeed(), WithHashSeed(3)).(*hashMap); true != m.seeded || 3 != m.seed {
+		t.Fatal()
+	}
+	if m := NewMap(WithHashSeed(3), WithoutHashSeed()).(*hashMap); false != m.seeded || 0 != m.seed {
+		t.Fatal()
+	}
+	// a seed of 0 is still mixed
+	if 5 == NewMap(WithHashSeed(0)).(*hashMap).hash(testKeyInt(5)) {
+		t.Fatal()
+	}
+	// the bucket is the seeded hash
+	a.Put(testKeyInt(5), "five")
+	if 1 != len(a.m[a.hash(testKeyInt(5))]) || nil != a.m[5] {
+		t.Fatal()
+	}
+}
+
+func TestNewMap_WithRandomHashSeed(t *testing.T) {
+	a := NewMap(WithRandomHashSeed()).(*hashMap)
+	b := NewMap(WithRandomHashSeed()).(*hashMap)
+	if true != a.seeded || true != b.seeded || a.seed == b.seed {
+		t.Fatal()
+	}
+	s := NewMap(WithOpenAddressing(), WithRandomHashSeed()).(*swissMap)
+	if 0 == s.seed || s.hash(testKeyInt(5)) == newSwissMap().hash(testKeyInt(5)) {
+		t.Fatal()
+	}
+}
+
+func TestNewMap_seeded(t *testing.T) {
+	for _, options := range [][]Option{
+		{},
+		{WithoutHashSeed()},
+		{WithHashSeed(0x1234)},
+		{WithRandomHashSeed()},
+		{WithOpenAddressing(), WithRandomHashSeed()},
+	} {
+		m := NewMap(options...).(ComputeMap)
+		for i := 0; i < 1000; i++ {
+			m.Put(testKeyInt(i), i)
+		}
+		for i := 0; i < 100; i++ {
+			m.Put(testKeyCollision(i), i)
+		}
+		m.Put(nil, "nil")
+		if 1101 != m.Size() || "nil" != m.Get(nil).(string) {
+			t.Fatal(m.Size())
+		}
+		for i := 0; i < 1000; i += 2 {
+			if i != m.Remove(testKeyInt(i)).(int) {
+				t.Fatal(i)
+			}
+		}
+		m.Merge(testKeyCollision(3), 1, func(old, new Value) Value {
+			return old.(int) + new.(int)
+		})
+		for i := 0; i < 1000; i++ {
+			if v := m.Get(testKeyInt(i)); (0 == i%2 && nil != v) || (1 == i%2 && i != v.(int)) {
+				t.Fatal(i, v)
+			}
+		}
+		if 4 != m.Get(testKeyCollision(3)).(int) || 601 != m.Size() || 601 != len(m.Pairs()) {
+			t.Fatal(m.Size())
+		}
+	}
+}
+
+func TestWithBucketLimit(t *testing.T) {
+	var errs []*BucketLimitError
+	m := NewMap(WithHashSeed(99), WithBucketLimit(3, func(err *BucketLimitError) {
+		errs = append(errs, err)
+	}))
+	for i := 0; i < 3; i++ {
+		m.Put(testKeyCollision(i), i)
+		m.Put(testKeyInt(i), i)
+	}
+	if 0 != len(errs) {
+		t.Fatal(errs)
+	}
+	m.Put(testKeyCollision(3), 3)
+	if 1 != len(errs) || testKeyCollision(3) != errs[0].Key || 7 != errs[0].Hash || 4 != errs[0].Size ||
+		3 != errs[0].Limit {
+		t.Fatal(errs)
+	}
+	if "4 keys share the hash 7, exceeding the bucket limit of 3, which may indicate hash flooding" != errs[0].Error() {
+		t.Fatal(errs[0].Error())
+	}
+	// replacing, or growing further, doesn't report again
+	m.Put(testKeyCollision(3), 4)
+	m.Put(testKeyCollision(4), 4)
+	if 1 != len(errs) || 4 != m.Get(testKeyCollision(3)).(int) {
+		t.Fatal(errs)
+	}
+	// until the bucket shrinks back to the limit
+	m.Remove(testKeyCollision(0))
+	m.Put(testKeyCollision(5), 5)
+	if 1 != len(errs) {
+		t.Fatal(errs)
+	}
+	m.Remove(testKeyCollision(1))
+	m.Remove(testKeyCollision(2))
+	m.Put(testKeyCollision(6), 6)
+	if 2 != len(errs) || testKeyCollision(6) != errs[1].Key {
+		t.Fatal(errs)
+	}
+	// the map was still modified
+	if 7 != m.Size() || 6 != m.Get(testKeyCollision(6)).(int) {
+		t.Fatal(m.Size())
+	}
+}
+
+func TestWithBucketLimit_panic(t *testing.T) {
+	for _, fn := range []func(){
+		func() { WithBucketLimit(0, func(err *BucketLimitError) {}) },
+		func() { WithBucketLimit(1, nil) },
+		func() { NewMap(WithOpenAddressing(), WithBucketLimit(1, func(err *BucketLimitError) {})) },
+		func() { NewConcurrentMap(WithBucketLimit(1, func(err *BucketLimitError) {}), WithOpenAddressing()) },
+	} {
+		func() {
+			defer func() {
+				r := recover()
+				if nil == r || false == strings.Contains(r.(error).Error(), "bucket limit") {
+					t.Fatal(r)
+				}
+			}()
+			fn()
+		}()
+	}
+}
@@ -0,0 +1,244 @@
+//go:build go1.23
+// +build go1.23
+
+/*
+   Copyright 2017 Joseph Cumines
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+
+ */
+
+package simhash
+
+import (
+	"iter"
+)
+
+// RangeMap is a Map that can also be iterated using range-over-func, which is implemented by every Map returned by
+// this package, and will not allocate the intermediate slices of Keys, Values and Pairs, with the exception of
+// NewConcurrentMap. The sequences may be iterated more than once, and stop early if the loop breaks. Unless documented
+// otherwise, they are fail-fast, the same as Iterator, and will panic with a *ConcurrentModificationError if the map
+// is structurally modified while they are being iterated.
+type RangeMap interface {
+	Map
+
+	// All returns a sequence of every key-value pair in the map, in the same order as Iterator, if the map has a
+	// defined order.
+	All() iter.Seq2[Key, Value]
+
+	// KeysSeq returns a sequence of the keys of All.
+	KeysSeq() iter.Seq[Key]
+
+	// ValuesSeq returns a sequence of the values of All.
+	ValuesSeq() iter.Seq[Value]
+}
+
+// All returns m.All, if m is a RangeMap, otherwise a sequence using m.Iterator.
+func All(m Map) iter.Seq2[Key, Value] {
+	if r, ok := m.(RangeMap); true == ok {
+		return r.All()
+	}
+	return func(yield func(Key, Value) bool) {
+		for it := m.Iterator(); true == it.Next(); {
+			if false == yield(it.Key(), it.Value()) {
+				return
+			}
+		}
+	}
+}
+
+// KeysSeq returns the keys of All(m).
+func KeysSeq(m Map) iter.Seq[Key] {
+	if r, ok := m.(RangeMap); true == ok {
+		return r.KeysSeq()
+	}
+	return keysSeq(All(m))
+}
+
+// ValuesSeq returns the values of All(m).
+func ValuesSeq(m Map) iter.Seq[Value] {
+	if r, ok := m.(RangeMap); true == ok {
+		return r.ValuesSeq()
+	}
+	return valuesSeq(All(m))
+}
+
+// Collect creates a new Map, using NewMap with options, and stores every pair in seq, where later pairs replace
+// earlier pairs with the same key.
+func Collect(seq iter.Seq2[Key, Value], options ...Option) Map {
+	m := NewMap(options...)
+	for key, value := range seq {
+		m.Put(key, value)
+	}
+	return m
+}
+
+func keysSeq(all iter.Seq2[Key, Value]) iter.Seq[Key] {
+	return func(yield func(Key) bool) {
+		for key := range all {
+			if false == yield(key) {
+				return
+			}
+		}
+	}
+}
+
+func valuesSeq(all iter.Seq2[Key, Value]) iter.Seq[Value] {
+	return func(yield func(Value) bool) {
+		for _, value := range all {
+			if false == yield(value) {
+				return
+			}
+		}
+	}
+}
+
+// checkModCount panics if modCount has changed from expected, unless weak is true.
+func checkModCount(expected, modCount int, weak bool) {
+	if false == weak && expected != modCount {
+		panic(&ConcurrentModificationError{})
+	}
+}
+
+func (m *hashMap) All() iter.Seq2[Key, Value] {
+	return func(yield func(Key, Value) bool) {
+		modCount := m.modCount
+		for h := range m.m {
+			// the bucket is read on each step, as it may be modified by yield, if the map is weakly consistent
+			for i := 0; i < len(m.m[h]); i++ {
+				pair := m.m[h][i]
+				if nil == pair {
+					continue
+				}
+				if false == yield(pair.Key(), pair.Value()) {
+					return
+				}
+				checkModCount(modCount, m.modCount, m.weakIterators)
+			}
+		}
+	}
+}
+
+func (m *hashMap) KeysSeq() iter.Seq[Key] {
+	return keysSeq(m.All())
+}
+
+func (m *hashMap) ValuesSeq() iter.Seq[Value] {
+	return valuesSeq(m.All())
+}
+
+func (m *swissMap) All() iter.Seq2[Key, Value] {
+	return func(yield func(Key, Value) bool) {
+		modCount := m.modCount
+		for g := 0; g < len(m.groups); g++ {
+			for i := 0; i < swissGroupSize && g < len(m.groups); i++ {
+				group := &m.groups[g]
+				if false == group.isFull(i) {
+					continue
+				}
+				if false == yield(group.keys[i], group.values[i]) {
+					return
+				}
+				checkModCount(modCount, m.modCount, m.weakIterators)
+			}
+		}
+	}
+}
+
+func (m *swissMap) KeysSeq() iter.Seq[Key] {
+	return keysSeq(m.All())
+}
+
+func (m *swissMap) ValuesSeq() iter.Seq[Value] {
+	return valuesSeq(m.All())
+}
+
+// All iterates the linked list directly, which will not affect the access order, but in access order, accessing any
+// key while iterating is a modification, and will panic, the same as Java's LinkedHashMap.
+func (m *linkedMap) All() iter.Seq2[Key, Value] {
+	return func(yield func(Key, Value) bool) {
+		modCount := m.modCount
+		for e := m.root.next; &m.root != e; e = e.next {
+			if false == yield(e.key, e.value) {
+				return
+			}
+			checkModCount(modCount, m.modCount, false)
+		}
+	}
+}
+
+func (m *linkedMap) KeysSeq() iter.Seq[Key] {
+	return keysSeq(m.All())
+}
+
+func (m *linkedMap) ValuesSeq() iter.Seq[Value] {
+	return valuesSeq(m.All())
+}
+
+func (m *sortedMap) All() iter.Seq2[Key, Value] {
+	return func(yield func(Key, Value) bool) {
+		modCount := m.t.modCount
+		m.t.walk(func(n *avlNode) bool {
+			if false == yield(n.key, n.value) {
+				return false
+			}
+			checkModCount(modCount, m.t.modCount, false)
+			return true
+		})
+	}
+}
+
+func (m *sortedMap) KeysSeq() iter.Seq[Key] {
+	return keysSeq(m.All())
+}
+
+func (m *sortedMap) ValuesSeq() iter.Seq[Value] {
+	return valuesSeq(m.All())
+}
+
+// All copies the pairs of each shard in turn, without holding the lock while yielding, so it is safe to modify the
+// map while iterating, and, like Iterator, is only consistent per-shard.
+func (m *concurrentMap) All() iter.Seq2[Key, Value] {
+	return func(yield func(Key, Value) bool) {
+		for _, s := range m.shards {
+			s.mutex.RLock()
+			pairs := s.m.Pairs()
+			s.mutex.RUnlock()
+			for _, pair := range pairs {
+				if false == yield(pair.Key(), pair.Value()) {
+					return
+				}
+			}
+		}
+	}
+}
+
+func (m *concurrentMap) KeysSeq() iter.Seq[Key] {
+	return keysSeq(m.All())
+}
+
+func (m *concurrentMap) ValuesSeq() iter.Seq[Value] {
+	return valuesSeq(m.All())
+}
+
+func (m *biMap) All() iter.Seq2[Key, Value] {
+	return m.forward.All()
+}
+
+func (m *biMap) KeysSeq() iter.Seq[Key] {
+	return m.forward.KeysSeq()
+}
+
+func (m *biMap) ValuesSeq() iter.Seq[Value] {
+	return m.forward.ValuesSeq()
+}
@@ -0,0 +1,300 @@
+//go:build go1.23
+// +build go1.23
+
+/*
+   Copyright 2017 Joseph Cumines
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+
+ */
+
+package simhash
+
+import (
+	"iter"
+	"testing"
+)
+
+// testRangeMaps returns every RangeMap implementation, which support testKeyCollision keys.
+func testRangeMaps() []struct {
+	name   string
+	newMap func() Map
+} {
+	return []struct {
+		name   string
+		newMap func() Map
+	}{
+		{"NewMap", func() Map { return NewMap() }},
+		{"WithWeaklyConsistentIterators", func() Map { return NewMap(WithWeaklyConsistentIterators()) }},
+		{"WithOpenAddressing", func() Map { return NewMap(WithOpenAddressing()) }},
+		{"NewLinkedMap", NewLinkedMap},
+		{"NewAccessOrderedLinkedMap", NewAccessOrderedLinkedMap},
+		{"NewLRU", func() Map { return NewLRU(100, nil) }},
+		{"NewSortedMap", func() Map { return NewSortedMap() }},
+		{"NewConcurrentMap", func() Map { return NewConcurrentMap() }},
+	}
+}
+
+func TestRangeMap(t *testing.T) {
+	for _, tc := range testRangeMaps() {
+		t.Run(tc.name, func(t *testing.T) {
+			m := tc.newMap()
+			r := m.(RangeMap)
+			for range r.All() {
+				t.Fatal("expected no pairs")
+			}
+			for i := 0; i < 40; i++ {
+				m.Put(testKeyCollision(i), i)
+			}
+			// the sequences may be iterated more than once
+			for x := 0; x < 2; x++ {
+				seen := make(map[Key]bool)
+				for k, v := range r.All() {
+					// Get would be a modification, in access order
+					if true == seen[k] || int(k.(testKeyCollision)) != v {
+						t.Fatal(k, v)
+					}
+					seen[k] = true
+				}
+				if 40 != len(seen) {
+					t.Fatal(len(seen))
+				}
+			}
+			keys, sum := 0, 0
+			for k := range r.KeysSeq() {
+				if false == m.Contains(k) {
+					t.Fatal(k)
+				}
+				keys++
+			}
+			for v := range r.ValuesSeq() {
+				sum += v.(int)
+			}
+			if 40 != keys || 780 != sum {
+				t.Fatal(keys, sum)
+			}
+			// breaking early
+			count := 0
+			for range r.All() {
+				count++
+				if 5 == count {
+					break
+				}
+			}
+			for range r.KeysSeq() {
+				count++
+				break
+			}
+			for range r.ValuesSeq() {
+				count++
+				break
+			}
+			if 7 != count {
+				t.Fatal(count)
+			}
+		})
+	}
+}
+
+func TestRangeMap_order(t *testing.T) {
+	for _, m := range []Map{NewLinkedMap(), NewSortedMap()} {
+		for _, i := range []int{3, 1, 4, 5, 9, 2, 6} {
+			m.Put(testKeyCollision(i), i)
+		}
+		it := m.Iterator()
+		for k, v := range m.(RangeMap).All() {
+			if false == it.Next() || k != it.Key() || v != it.Value() {
+				t.Fatal(k, it.Key())
+			}
+		}
+		if true == it.Next() {
+			t.Fatal(it.Key())
+		}
+	}
+}
+
+func TestRangeMap_biMap(t *testing.T) {
+	m := NewBiMap()
+	m.Put(testKeyInt(1), testKeyInt(2))
+	m.Put(testKeyInt(3), testKeyInt(4))
+	count := 0
+	for k, v := range m.(RangeMap).All() {
+		if m.Get(k) != v || m.Inverse().Get(v.(Key)) != k {
+			t.Fatal(k, v)
+		}
+		count++
+	}
+	for k := range m.Inverse().(RangeMap).KeysSeq() {
+		if false == m.Inverse().Contains(k) {
+			t.Fatal(k)
+		}
+		count++
+	}
+	for v := range m.(RangeMap).ValuesSeq() {
+		if false == m.Inverse().Contains(v.(Key)) {
+			t.Fatal(v)
+		}
+		count++
+	}
+	if 6 != count {
+		t.Fatal(count)
+	}
+}
+
+// expectRangeConcurrentModification ranges over seq, calling fn for the first pair, and fails unless it panics with a
+// *ConcurrentModificationError.
+func expectRangeConcurrentModification(t *testing.T, seq iter.Seq2[Key, Value], fn func()) {
+	t.Helper()
+	expectConcurrentModification(t, func() bool {
+		for range seq {
+			fn()
+		}
+		return false
+	})
+}
+
+func TestRangeMap_concurrentModification(t *testing.T) {
+	for _, tc := range testRangeMaps() {
+		t.Run(tc.name, func(t *testing.T) {
+			m := tc.newMap()
+			for i := 0; i < 10; i++ {
+				m.Put(testKeyCollision(i), i)
+			}
+			all := m.(RangeMap).All()
+			// replacing values is not a structural modification, except in access order
+			if "NewAccessOrderedLinkedMap" != tc.name && "NewLRU" != tc.name {
+				for k, v := range all {
+					m.Put(k, v.(int)+1)
+				}
+			}
+			next := 10
+			add := func() {
+				m.Put(testKeyCollision(next), next)
+				next++
+			}
+			switch tc.name {
+			case "WithWeaklyConsistentIterators", "NewConcurrentMap":
+				// new keys may be returned, so only add a limited number
+				for range all {
+					if next < 20 {
+						add()
+					}
+				}
+				if 20 != m.Size() {
+					t.Fatal(m.Size())
+				}
+			default:
+				expectRangeConcurrentModification(t, all, add)
+				expectRangeConcurrentModification(t, all, func() {
+					m.Remove(testKeyCollision(0))
+				})
+			}
+		})
+	}
+	// accessing a key is a modification, in access order
+	m := NewAccessOrderedLinkedMap()
+	m.Put(testKeyInt(1), 1)
+	m.Put(testKeyInt(2), 2)
+	expectRangeConcurrentModification(t, m.(RangeMap).All(), func() {
+		m.Get(testKeyInt(1))
+	})
+}
+
+// testPlainMap hides the RangeMap methods of a Map.
+type testPlainMap struct {
+	Map
+}
+
+func TestAll(t *testing.T) {
+	linked := NewLinkedMap()
+	for i := 0; i < 10; i++ {
+		linked.Put(testKeyInt(i), i*2)
+	}
+	for _, m := range []Map{linked, testPlainMap{linked}} {
+		i := 0
+		for k, v := range All(m) {
+			if testKeyInt(i) != k || i*2 != v {
+				t.Fatal(k, v)
+			}
+			i++
+			if 5 == i {
+				break
+			}
+		}
+		i = 0
+		for k := range KeysSeq(m) {
+			if testKeyInt(i) != k {
+				t.Fatal(k)
+			}
+			i++
+		}
+		for v := range ValuesSeq(m) {
+			if (i-10)*2 != v {
+				t.Fatal(v)
+			}
+			i++
+		}
+		if 20 != i {
+			t.Fatal(i)
+		}
+	}
+}
+
+func TestCollect(t *testing.T) {
+	m := NewMap()
+	for i := 0; i < 100; i++ {
+		m.Put(testKeyStruct{i % 7, i}, i)
+	}
+	m.Put(nil, nil)
+	for _, c := range []Map{Collect(All(m)), Collect(All(m), WithOpenAddressing())} {
+		if 101 != c.Size() || false == c.Contains(nil) {
+			t.Fatal(c.Size())
+		}
+		for k, v := range All(m) {
+			if c.Get(k) != v {
+				t.Fatal(k, v)
+			}
+		}
+	}
+	// later pairs replace earlier pairs
+	c := Collect(func(yield func(Key, Value) bool) {
+		_ = yield(testKeyInt(1), 1) && yield(testKeyInt(2), 2) && yield(testKeyInt(1), 3)
+	})
+	if 2 != c.Size() || 3 != c.Get(testKeyInt(1)) {
+		t.Fatal(c.Serialize())
+	}
+	if 0 != Collect(func(yield func(Key, Value) bool) {}).Size() {
+		t.Fatal()
+	}
+}
+
+func TestRangeMap_allocations(t *testing.T) {
+	for _, tc := range testRangeMaps() {
+		if "NewConcurrentMap" == tc.name {
+			continue
+		}
+		m := tc.newMap().(RangeMap)
+		for i := 0; i < 1000; i++ {
+			m.Put(testKeyCollision(i), i)
+		}
+		allocs := testing.AllocsPerRun(10, func() {
+			for range m.All() {
+			}
+			for range m.KeysSeq() {
+			}
+		})
+		if allocs > 8 {
+			t.Errorf("%s: unexpected allocations: %v", tc.name, allocs)
+		}
+	}
+}
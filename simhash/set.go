@@ -0,0 +1,149 @@
+/*
+   Copyright 2017 Joseph Cumines
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+
+ */
+
+package simhash
+
+// Set is a collection of unique keys, using the same hashing and equality as Map.
+type Set interface {
+	// Add will add key to the set, and will return true if it didn't already exist.
+	Add(key Key) bool
+
+	// Remove will remove key from the set, and will return true if it existed.
+	Remove(key Key) bool
+
+	// Contains will return true if the key exists in the set.
+	Contains(key Key) bool
+
+	// Size returns the number of keys in the set.
+	Size() int
+
+	// Get a new Iterator for this set, where each pair has the key, and a nil value. It is fail-fast, the same as
+	// the iterator of a Map, and is not a MutableIterator.
+	Iterator() Iterator
+
+	// Slice returns a slice containing all the keys in the set.
+	Slice() []Key
+}
+
+// hashSet implements Set using a hashMap with nil values, it has the same concurrency restrictions.
+type hashSet struct {
+	m *hashMap
+}
+
+func (s *hashSet) Add(key Key) bool {
+	h := s.m.hash(key)
+	if _, ok := s.m.find(h, key); true == ok {
+		return false
+	}
+	s.m.insert(h, NewPair(key, nil))
+	return true
+}
+
+func (s *hashSet) Remove(key Key) bool {
+	h, i, ok := s.m.lookup(key)
+	if false == ok {
+		return false
+	}
+	s.m.removeAt(h, i)
+	return true
+}
+
+func (s *hashSet) Contains(key Key) bool {
+	return s.m.Contains(key)
+}
+
+func (s *hashSet) Size() int {
+	return s.m.Size()
+}
+
+func (s *hashSet) Iterator() Iterator {
+	return readOnlyIterator{s.m.Iterator()}
+}
+
+func (s *hashSet) Slice() []Key {
+	return s.m.Keys()
+}
+
+// NewSet creates a new Set containing keys. It is not safe for concurrent use.
+func NewSet(keys ...Key) Set {
+	s := &hashSet{NewMap().(*hashMap)}
+	for _, key := range keys {
+		s.Add(key)
+	}
+	return s
+}
+
+// Union returns a new Set containing every key that is in either a or b.
+func Union(a, b Set) Set {
+	if a.Size() < b.Size() {
+		a, b = b, a
+	}
+	s := NewSet(a.Slice()...)
+	for it := b.Iterator(); true == it.Next(); {
+		s.Add(it.Key())
+	}
+	return s
+}
+
+// Intersection returns a new Set containing every key that is in both a and b.
+func Intersection(a, b Set) Set {
+	if a.Size() > b.Size() {
+		a, b = b, a
+	}
+	s := NewSet()
+	for it := a.Iterator(); true == it.Next(); {
+		if true == b.Contains(it.Key()) {
+			s.Add(it.Key())
+		}
+	}
+	return s
+}
+
+// Difference returns a new Set containing every key that is in a, but not in b.
+func Difference(a, b Set) Set {
+	s := NewSet()
+	for it := a.Iterator(); true == it.Next(); {
+		if false == b.Contains(it.Key()) {
+			s.Add(it.Key())
+		}
+	}
+	return s
+}
+
+// SymmetricDifference returns a new Set containing every key that is in exactly one of a or b.
+func SymmetricDifference(a, b Set) Set {
+	s := Difference(a, b)
+	for it := b.Iterator(); true == it.Next(); {
+		if false == a.Contains(it.Key()) {
+			s.Add(it.Key())
+		}
+	}
+	return s
+}
+
+// IsSubsetOf returns true if every key in a is also in b.
+func IsSubsetOf(a, b Set) bool {
+	if a.Size() > b.Size() {
+		return false
+	}
+	for it := a.Iterator(); true == it.Next(); {
+		if false == b.Contains(it.Key()) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,117 @@
+/*
+   Copyright 2017 Joseph Cumines
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+
+ */
+
+package simhash
+
+import (
+	"sort"
+	"testing"
+)
+
+func newTestIntSet(values ...int) Set {
+	s := NewSet()
+	for _, v := range values {
+		s.Add(testKeyInt(v))
+	}
+	return s
+}
+
+func sortedSetValues(s Set) []int {
+	values := make([]int, 0)
+	for _, k := range s.Slice() {
+		values = append(values, int(k.(testKeyInt)))
+	}
+	sort.Ints(values)
+	return values
+}
+
+func TestNewSet(t *testing.T) {
+	s := NewSet(testKeyInt(1), testKeyInt(2), testKeyInt(1), nil)
+	if 3 != s.Size() || true != s.Contains(nil) || true != s.Contains(testKeyInt(2)) || true == s.Contains(testKeyInt(3)) {
+		t.Fatal()
+	}
+}
+
+func TestHashSet_Add(t *testing.T) {
+	s := NewSet()
+	if true != s.Add(testKeyInt(1)) || false != s.Add(testKeyInt(1)) || true != s.Add(nil) || false != s.Add(nil) || 2 != s.Size() {
+		t.Fatal()
+	}
+}
+
+func TestHashSet_Remove(t *testing.T) {
+	s := newTestIntSet(1, 2, 3)
+	if true != s.Remove(testKeyInt(2)) || false != s.Remove(testKeyInt(2)) || false != s.Remove(nil) || 2 != s.Size() {
+		t.Fatal()
+	}
+	if false == equalInts([]int{1, 3}, sortedSetValues(s)) {
+		t.Fatal(sortedSetValues(s))
+	}
+}
+
+func TestHashSet_Iterator(t *testing.T) {
+	s := newTestIntSet(1, 2, 3)
+	values := make([]int, 0)
+	for it := s.Iterator(); true == it.Next(); {
+		if nil != it.Value() {
+			t.Fatal()
+		}
+		values = append(values, int(it.Key().(testKeyInt)))
+	}
+	sort.Ints(values)
+	if false == equalInts([]int{1, 2, 3}, values) {
+		t.Fatal(values)
+	}
+}
+
+func TestSetAlgebra(t *testing.T) {
+	a := newTestIntSet(1, 2, 3, 4)
+	b := newTestIntSet(3, 4, 5)
+	for _, c := range []struct {
+		name     string
+		actual   Set
+		expected []int
+	}{
+		{"union", Union(a, b), []int{1, 2, 3, 4, 5}},
+		{"union reversed", Union(b, a), []int{1, 2, 3, 4, 5}},
+		{"intersection", Intersection(a, b), []int{3, 4}},
+		{"intersection reversed", Intersection(b, a), []int{3, 4}},
+		{"difference", Difference(a, b), []int{1, 2}},
+		{"difference reversed", Difference(b, a), []int{5}},
+		{"symmetric difference", SymmetricDifference(a, b), []int{1, 2, 5}},
+		{"symmetric difference reversed", SymmetricDifference(b, a), []int{1, 2, 5}},
+		{"empty", Intersection(a, NewSet()), []int{}},
+	} {
+		if false == equalInts(c.expected, sortedSetValues(c.actual)) {
+			t.Error(c.name, sortedSetValues(c.actual))
+		}
+	}
+	if 4 != a.Size() || 3 != b.Size() {
+		t.Fatal("inputs were modified")
+	}
+}
+
+func TestIsSubsetOf(t *testing.T) {
+	a := newTestIntSet(1, 2, 3, 4)
+	if true != IsSubsetOf(newTestIntSet(2, 3), a) ||
+		true != IsSubsetOf(NewSet(), a) ||
+		true != IsSubsetOf(a, a) ||
+		false != IsSubsetOf(newTestIntSet(2, 5), a) ||
+		false != IsSubsetOf(a, newTestIntSet(1, 2, 3)) {
+		t.Fatal()
+	}
+}
@@ -0,0 +1,242 @@
+/*
+   Copyright 2017 Joseph Cumines
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+
+ */
+
+// Package simhashtest provides utilities for testing implementations of the interfaces in the simhash package.
+package simhashtest
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/joeycumines/go-hashmap/simhash"
+)
+
+const (
+	// keyMaxSize is the largest size passed to each Generator, by CheckKeyContract.
+	keyMaxSize = 32
+
+	// keySeedsPerSize is the number of keys generated by each Generator, for each size, by CheckKeyContract.
+	keySeedsPerSize = 8
+)
+
+// Generator returns a new key, using r as it's only source of randomness, where size bounds the complexity of the
+// key, e.g. the length of a string, or the magnitude of an int, and will start at 0. Generators must be
+// deterministic, that is, given an r with the same seed, and the same size, they must return equal keys. They should
+// often return equal keys for small sizes, as only keys which are equal exercise most of the contract.
+type Generator func(r *rand.Rand, size int) simhash.Key
+
+// keySample is a generated key, along with how to reproduce it.
+type keySample struct {
+	key       simhash.Key
+	generator int
+	size      int
+	seed      int64
+}
+
+func (s keySample) String() string {
+	return fmt.Sprintf("%#v (generator %d, size %d, seed %d)", s.key, s.generator, s.size, s.seed)
+}
+
+// foreignKey is a key type which no other key should be equal to.
+type foreignKey struct{}
+
+func (foreignKey) Hash() int {
+	return 0
+}
+
+func (foreignKey) Equals(other interface{}) bool {
+	_, ok := other.(foreignKey)
+	return ok
+}
+
+// keyChecker reports at most one failure for each property, which will be from the smallest size possible, as
+// sizes are checked in ascending order.
+type keyChecker struct {
+	t      testing.TB
+	failed map[string]bool
+}
+
+func (c *keyChecker) fail(property string, format string, args ...interface{}) {
+	c.t.Helper()
+	if true == c.failed[property] {
+		return
+	}
+	c.failed[property] = true
+	c.t.Errorf("key contract violated, %s: %s", property, fmt.Sprintf(format, args...))
+}
+
+// catch calls fn, returning any value it panicked with, or nil.
+func catch(fn func()) (r interface{}) {
+	defer func() {
+		r = recover()
+	}()
+	fn()
+	return
+}
+
+// equals calls a.Equals(b), reporting a panic as a failure, in which case it returns false.
+func (c *keyChecker) equals(a keySample, b interface{}) (result bool) {
+	c.t.Helper()
+	if r := catch(func() { result = a.key.Equals(b) }); nil != r {
+		c.fail("equals panicked", "%s.Equals(%#v) panicked: %v", a, b, r)
+		return false
+	}
+	return result
+}
+
+// hash returns the hash, and 64 bit hash, if the key implements Key64, reporting a panic as a failure.
+func (c *keyChecker) hash(a keySample) (h int, h64 uint64, ok bool) {
+	c.t.Helper()
+	if r := catch(func() {
+		h = a.key.Hash()
+		if k, is64 := a.key.(simhash.Key64); true == is64 {
+			h64 = k.Hash64()
+		}
+	}); nil != r {
+		c.fail("hash panicked", "%s.Hash() panicked: %v", a, r)
+		return 0, 0, false
+	}
+	return h, h64, true
+}
+
+// compare returns the sign of a.Compare(b), if both keys are Comparable, and have the same type.
+func (c *keyChecker) compare(a, b keySample) (sign int, ok bool) {
+	c.t.Helper()
+	ac, aok := a.key.(simhash.Comparable)
+	if false == aok || reflect.TypeOf(a.key) != reflect.TypeOf(b.key) {
+		return 0, false
+	}
+	var result int
+	if r := catch(func() { result = ac.Compare(b.key) }); nil != r {
+		c.fail("compare panicked", "%s.Compare(%s) panicked: %v", a, b, r)
+		return 0, false
+	}
+	switch {
+	case result < 0:
+		return -1, true
+	case result > 0:
+		return 1, true
+	}
+	return 0, true
+}
+
+// checkSingle checks the properties of a single key, where b was generated using the same seed.
+func (c *keyChecker) checkSingle(a keySample, b simhash.Key) {
+	c.t.Helper()
+	if false == c.equals(a, a.key) {
+		c.fail("reflexivity", "%s does not equal itself", a)
+	} else if false == c.equals(a, b) {
+		c.fail("determinism", "%s does not equal %#v, generated using the same seed", a, b)
+	}
+	if true == c.equals(a, nil) {
+		c.fail("nil handling", "%s equals nil", a)
+	}
+	if true == c.equals(a, foreignKey{}) {
+		c.fail("foreign types", "%s equals a key of an unrelated type", a)
+	}
+	// hashes must be stable across calls, and equal instances
+	h1, h64a, ok1 := c.hash(a)
+	h2, h64b, ok2 := c.hash(a)
+	h3, h64c, ok3 := c.hash(keySample{b, a.generator, a.size, a.seed})
+	if true == ok1 && true == ok2 && (h1 != h2 || h64a != h64b) {
+		c.fail("stability", "%s returned different hashes across calls, %d, %d", a, h1, h2)
+	}
+	if true == ok1 && true == ok3 && (h1 != h3 || h64a != h64c) {
+		c.fail("determinism", "%s has a different hash to %#v, generated using the same seed", a, b)
+	}
+	if sign, ok := c.compare(a, a); true == ok && 0 != sign {
+		c.fail("comparison", "%s does not compare equal to itself", a)
+	}
+}
+
+// checkPairs checks the properties between every pair, and triple, of samples.
+func (c *keyChecker) checkPairs(samples []keySample) {
+	c.t.Helper()
+	eq := make([][]bool, len(samples))
+	for i, a := range samples {
+		eq[i] = make([]bool, len(samples))
+		for j, b := range samples {
+			eq[i][j] = c.equals(a, b.key)
+		}
+	}
+	for i, a := range samples {
+		for j, b := range samples {
+			if eq[i][j] != eq[j][i] {
+				c.fail("symmetry", "%s.Equals(%s) is %t, but the reverse is %t", a, b, eq[i][j], eq[j][i])
+			}
+			if sign, ok := c.compare(a, b); true == ok && (0 == sign) != eq[i][j] {
+				c.fail("comparison", "%s.Compare(%s) is %d, but Equals is %t", a, b, sign, eq[i][j])
+			} else if reverse, rok := c.compare(b, a); true == ok && true == rok && sign != -reverse {
+				c.fail("comparison", "%s.Compare(%s) has sign %d, but the reverse has sign %d", a, b, sign, reverse)
+			}
+			if false == eq[i][j] {
+				continue
+			}
+			ha, h64a, aok := c.hash(a)
+			hb, h64b, bok := c.hash(b)
+			if true == aok && true == bok && (ha != hb || h64a != h64b) {
+				c.fail("hash consistency", "%s equals %s, but their hashes differ, %d, %d", a, b, ha, hb)
+			}
+			for k, d := range samples {
+				if true == eq[j][k] && false == eq[i][k] {
+					c.fail("transitivity", "%s equals %s, which equals %s, but the first does not equal the last", a, b, d)
+				}
+			}
+		}
+	}
+}
+
+// CheckKeyContract property tests the keys returned by generators, reporting any violation of the contract of
+// simhash.Key, using t.Errorf, where each is reported at most once, using the smallest size which caused it, along
+// with the seed that generated the key, to allow it to be reproduced. The properties checked are:
+//
+//   - reflexivity, every key equals itself
+//   - symmetry, a.Equals(b) if and only if b.Equals(a)
+//   - transitivity, if a.Equals(b) and b.Equals(c), then a.Equals(c)
+//   - hash consistency, equal keys have equal hashes, including Hash64, if they implement simhash.Key64
+//   - nil handling, no key equals nil, or a key of an unrelated type, and Equals never panics
+//   - stability, hashes do not change across calls, and generators are deterministic
+//   - comparison, if the keys implement simhash.Comparable, Compare is consistent with Equals, and antisymmetric
+//
+// Keys from different generators are compared with each other, which must be safe.
+func CheckKeyContract(t testing.TB, generators ...Generator) {
+	t.Helper()
+	if 0 == len(generators) {
+		t.Errorf("no generators")
+		return
+	}
+	c := &keyChecker{t: t, failed: make(map[string]bool)}
+	for size := 0; size <= keyMaxSize; size++ {
+		samples := make([]keySample, 0, len(generators)*keySeedsPerSize)
+		for g, generator := range generators {
+			for i := 0; i < keySeedsPerSize; i++ {
+				seed := int64(size*keySeedsPerSize + i)
+				a := keySample{generator(rand.New(rand.NewSource(seed)), size), g, size, seed}
+				b := generator(rand.New(rand.NewSource(seed)), size)
+				if nil == a.key || nil == b {
+					c.fail("nil handling", "generator %d returned nil, for size %d, seed %d", g, size, seed)
+					continue
+				}
+				c.checkSingle(a, b)
+				samples = append(samples, a)
+			}
+		}
+		c.checkPairs(samples)
+	}
+}
@@ -0,0 +1,260 @@
+/*
+   Copyright 2017 Joseph Cumines
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+
+ */
+
+package simhashtest
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/joeycumines/go-hashmap/simhash"
+	"github.com/joeycumines/go-hashmap/simhash/keys"
+)
+
+// recorder is a testing.TB which records errors, rather than failing the test.
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+// properties returns the properties of each error recorded.
+func (r *recorder) properties() []string {
+	properties := make([]string, 0, len(r.errors))
+	for _, err := range r.errors {
+		properties = append(properties, strings.SplitN(strings.TrimPrefix(err, "key contract violated, "), ":", 2)[0])
+	}
+	return properties
+}
+
+func intGenerator(r *rand.Rand, size int) simhash.Key {
+	return keys.IntKey(r.Intn(size + 1))
+}
+
+func stringGenerator(r *rand.Rand, size int) simhash.Key {
+	b := make([]byte, r.Intn(size+1))
+	for i := range b {
+		b[i] = "ab"[r.Intn(2)]
+	}
+	return keys.StringKey(b)
+}
+
+func tupleGenerator(r *rand.Rand, size int) simhash.Key {
+	return keys.Tuple(intGenerator(r, size), stringGenerator(r, size/2))
+}
+
+// testKeyBroken has an Equals and Hash which may be replaced, to break the contract.
+type testKeyBroken struct {
+	val     int
+	equals  func(a, b int) bool
+	hash    func(a int) int
+	compare func(a, b int) int
+}
+
+func (k testKeyBroken) Hash() int {
+	if nil != k.hash {
+		return k.hash(k.val)
+	}
+	return k.val
+}
+
+func (k testKeyBroken) Equals(other interface{}) bool {
+	o, ok := other.(testKeyBroken)
+	if false == ok {
+		return false
+	}
+	if nil != k.equals {
+		return k.equals(k.val, o.val)
+	}
+	return k.val == o.val
+}
+
+// testKeyBrokenComparable is a comparable testKeyBroken.
+type testKeyBrokenComparable struct {
+	testKeyBroken
+}
+
+func (k testKeyBrokenComparable) Equals(other interface{}) bool {
+	o, ok := other.(testKeyBrokenComparable)
+	return ok && k.testKeyBroken.Equals(o.testKeyBroken)
+}
+
+func (k testKeyBrokenComparable) Compare(other interface{}) int {
+	return k.compare(k.val, other.(testKeyBrokenComparable).val)
+}
+
+// testKeyPanic will panic if compared to anything other than a testKeyPanic.
+type testKeyPanic int
+
+func (k testKeyPanic) Hash() int {
+	return int(k)
+}
+
+func (k testKeyPanic) Equals(other interface{}) bool {
+	return k == other.(testKeyPanic)
+}
+
+// testKeyNil equals nil.
+type testKeyNil int
+
+func (k testKeyNil) Hash() int {
+	return int(k)
+}
+
+func (k testKeyNil) Equals(other interface{}) bool {
+	o, ok := other.(testKeyNil)
+	return nil == other || (ok && k == o)
+}
+
+func brokenGenerator(k testKeyBroken) Generator {
+	return func(r *rand.Rand, size int) simhash.Key {
+		k.val = r.Intn(size + 1)
+		return k
+	}
+}
+
+func TestCheckKeyContract_valid(t *testing.T) {
+	CheckKeyContract(t, intGenerator)
+	CheckKeyContract(t, intGenerator, stringGenerator, tupleGenerator)
+	CheckKeyContract(t, func(r *rand.Rand, size int) simhash.Key {
+		return simhash.ReflectKey([]int{r.Intn(size + 1), r.Intn(2)})
+	})
+	CheckKeyContract(t, brokenGenerator(testKeyBroken{}))
+	CheckKeyContract(t, func(r *rand.Rand, size int) simhash.Key {
+		return testKeyBrokenComparable{testKeyBroken{val: r.Intn(size + 1), compare: func(a, b int) int { return a - b }}}
+	})
+}
+
+func TestCheckKeyContract_invalid(t *testing.T) {
+	var counter int
+	for _, tc := range []struct {
+		name       string
+		generators []Generator
+		expected   string
+	}{
+		{
+			"no generators",
+			nil,
+			"no generators",
+		},
+		{
+			"reflexivity",
+			[]Generator{brokenGenerator(testKeyBroken{equals: func(a, b int) bool { return a != b }})},
+			"reflexivity",
+		},
+		{
+			"symmetry",
+			[]Generator{brokenGenerator(testKeyBroken{equals: func(a, b int) bool { return a <= b }, hash: func(int) int { return 0 }})},
+			"symmetry",
+		},
+		{
+			"transitivity",
+			[]Generator{brokenGenerator(testKeyBroken{equals: func(a, b int) bool { return a-b <= 1 && b-a <= 1 }, hash: func(int) int { return 0 }})},
+			"transitivity",
+		},
+		{
+			"hash consistency",
+			[]Generator{brokenGenerator(testKeyBroken{equals: func(a, b int) bool { return true }})},
+			"hash consistency",
+		},
+		{
+			"stability",
+			[]Generator{brokenGenerator(testKeyBroken{hash: func(a int) int { counter++; return counter }})},
+			"stability",
+		},
+		{
+			"determinism",
+			[]Generator{func(r *rand.Rand, size int) simhash.Key { counter++; return keys.IntKey(counter) }},
+			"determinism",
+		},
+		{
+			"nil equality",
+			[]Generator{func(r *rand.Rand, size int) simhash.Key { return testKeyNil(r.Intn(size + 1)) }},
+			"nil handling",
+		},
+		{
+			"nil generated",
+			[]Generator{func(r *rand.Rand, size int) simhash.Key { return nil }},
+			"nil handling",
+		},
+		{
+			"equals panicked",
+			[]Generator{func(r *rand.Rand, size int) simhash.Key { return testKeyPanic(r.Intn(size + 1)) }},
+			"equals panicked",
+		},
+		{
+			"hash panicked",
+			[]Generator{brokenGenerator(testKeyBroken{hash: func(a int) int { panic("hash") }})},
+			"hash panicked",
+		},
+		{
+			"comparison",
+			[]Generator{func(r *rand.Rand, size int) simhash.Key {
+				return testKeyBrokenComparable{testKeyBroken{val: r.Intn(size + 1), compare: func(a, b int) int { return 1 }}}
+			}},
+			"comparison",
+		},
+	} {
+		r := &recorder{}
+		CheckKeyContract(r, tc.generators...)
+		if 0 == len(r.errors) {
+			t.Errorf("%s: expected errors", tc.name)
+			continue
+		}
+		found := false
+		for _, property := range r.properties() {
+			found = found || tc.expected == property
+		}
+		if false == found {
+			t.Errorf("%s: unexpected errors: %v", tc.name, r.errors)
+		}
+		// each property is only reported once
+		seen := make(map[string]bool)
+		for _, property := range r.properties() {
+			if true == seen[property] {
+				t.Errorf("%s: duplicate errors: %v", tc.name, r.errors)
+			}
+			seen[property] = true
+		}
+	}
+}
+
+func TestCheckKeyContract_minimal(t *testing.T) {
+	// only keys of at least 5 are broken, which will first be generated with size 5
+	r := &recorder{}
+	CheckKeyContract(r, brokenGenerator(testKeyBroken{equals: func(a, b int) bool { return a == b && a < 5 }}))
+	if 1 != len(r.errors) || false == strings.Contains(r.errors[0], "reflexivity") ||
+		false == strings.Contains(r.errors[0], "size 5,") {
+		t.Fatal(r.errors)
+	}
+}
+
+func TestCheckKeyContract_mixedGenerators(t *testing.T) {
+	// testKeyPanic is only unsafe when compared to other types
+	r := &recorder{}
+	CheckKeyContract(r, intGenerator, func(r *rand.Rand, size int) simhash.Key { return testKeyPanic(r.Intn(size + 1)) })
+	if 1 != len(r.errors) || false == strings.Contains(r.errors[0], "equals panicked") {
+		t.Fatal(r.errors)
+	}
+}
@@ -0,0 +1,542 @@
+/*
+   Copyright 2017 Joseph Cumines
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+
+ */
+
+package simhashtest
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"testing"
+
+	"github.com/joeycumines/go-hashmap/simhash"
+)
+
+// conformanceKey is the key used by RunMapConformance, which has a poor hash, to cause collisions, and is Comparable,
+// so it may be used with a SortedMap.
+type conformanceKey int
+
+func (k conformanceKey) Hash() int {
+	return int(k) % 3
+}
+
+func (k conformanceKey) Equals(other interface{}) bool {
+	o, ok := other.(conformanceKey)
+	return ok && k == o
+}
+
+func (k conformanceKey) Compare(other interface{}) int {
+	return int(k) - int(other.(conformanceKey))
+}
+
+func (k conformanceKey) String() string {
+	return "k" + strconv.Itoa(int(k))
+}
+
+// ConformanceOption configures RunMapConformance.
+type ConformanceOption func(c *conformanceConfig)
+
+type conformanceConfig struct {
+	nilKeys bool
+}
+
+// WithoutNilKeys skips the parts of RunMapConformance that store a nil key, for implementations that don't support
+// them, e.g. simhash.SortedMap.
+func WithoutNilKeys() ConformanceOption {
+	return func(c *conformanceConfig) {
+		c.nilKeys = false
+	}
+}
+
+// mapModel is a reference implementation of the parts of simhash.Map that can be verified, which is simple enough
+// to be obviously correct, storing pairs in a slice.
+type mapModel struct {
+	keys   []simhash.Key
+	values []simhash.Value
+}
+
+func keysEqual(a, b simhash.Key) bool {
+	return (nil == a && nil == b) || (nil != a && nil != b && a.Equals(b))
+}
+
+func (m *mapModel) index(key simhash.Key) int {
+	for i, k := range m.keys {
+		if true == keysEqual(k, key) {
+			return i
+		}
+	}
+	return -1
+}
+
+func (m *mapModel) get(key simhash.Key) (simhash.Value, bool) {
+	if i := m.index(key); -1 != i {
+		return m.values[i], true
+	}
+	return nil, false
+}
+
+func (m *mapModel) put(key simhash.Key, value simhash.Value) simhash.Value {
+	if i := m.index(key); -1 != i {
+		old := m.values[i]
+		m.values[i] = value
+		return old
+	}
+	m.keys = append(m.keys, key)
+	m.values = append(m.values, value)
+	return nil
+}
+
+func (m *mapModel) remove(key simhash.Key) simhash.Value {
+	i := m.index(key)
+	if -1 == i {
+		return nil
+	}
+	old := m.values[i]
+	m.keys = append(m.keys[:i], m.keys[i+1:]...)
+	m.values = append(m.values[:i], m.values[i+1:]...)
+	return old
+}
+
+// describe formats a key for failure messages.
+func describe(key simhash.Key) string {
+	if nil == key {
+		return "<nil>"
+	}
+	return fmt.Sprintf("%v", key)
+}
+
+// verifyMap checks that every method of m that doesn't modify it is consistent with model.
+func verifyMap(t testing.TB, m simhash.Map, model *mapModel) {
+	t.Helper()
+	if size := m.Size(); len(model.keys) != size {
+		t.Fatalf("expected size %d, actual %d", len(model.keys), size)
+	}
+	for i, key := range model.keys {
+		if false == m.Contains(key) {
+			t.Fatalf("expected key %s to exist", describe(key))
+		}
+		if v := m.Get(key); model.values[i] != v {
+			t.Fatalf("expected key %s to have value %v, actual %v", describe(key), model.values[i], v)
+		}
+	}
+	if keys := m.Keys(); len(keys) != len(model.keys) {
+		t.Fatalf("expected %d keys, actual %v", len(model.keys), keys)
+	} else {
+		verifyDistinct(t, "Keys", keys, model)
+	}
+	if values := m.Values(); len(values) != len(model.values) {
+		t.Fatalf("expected %d values, actual %v", len(model.values), values)
+	} else {
+		counts := make(map[simhash.Value]int)
+		for _, v := range model.values {
+			counts[v]++
+		}
+		for _, v := range values {
+			counts[v]--
+		}
+		for v, count := range counts {
+			if 0 != count {
+				t.Fatalf("unexpected count of value %v in %v", v, values)
+			}
+		}
+	}
+	pairs := m.Pairs()
+	if len(pairs) != len(model.keys) {
+		t.Fatalf("expected %d pairs, actual %v", len(model.keys), pairs)
+	}
+	keys := make([]simhash.Key, 0, len(pairs))
+	for _, pair := range pairs {
+		if nil == pair {
+			t.Fatalf("nil pair in %v", pairs)
+		}
+		keys = append(keys, pair.Key())
+		if v, _ := model.get(pair.Key()); v != pair.Value() {
+			t.Fatalf("expected pair %s to have value %v, actual %v", describe(pair.Key()), v, pair.Value())
+		}
+	}
+	verifyDistinct(t, "Pairs", keys, model)
+	serialized := m.Serialize()
+	if len(serialized) != len(model.keys) {
+		t.Fatalf("expected %d serialized keys, actual %v", len(model.keys), serialized)
+	}
+	for i, key := range model.keys {
+		if v, ok := serialized[describe(key)]; false == ok || model.values[i] != v {
+			t.Fatalf("expected serialized key %s to have value %v, actual %v", describe(key), model.values[i], serialized)
+		}
+	}
+	verifyIterator(t, m, model)
+}
+
+// verifyDistinct checks that keys contains every key in model, exactly once.
+func verifyDistinct(t testing.TB, source string, keys []simhash.Key, model *mapModel) {
+	t.Helper()
+	seen := make([]bool, len(model.keys))
+	for _, key := range keys {
+		i := model.index(key)
+		if -1 == i {
+			t.Fatalf("%s returned unexpected key %s", source, describe(key))
+		}
+		if true == seen[i] {
+			t.Fatalf("%s returned duplicate key %s", source, describe(key))
+		}
+		seen[i] = true
+	}
+}
+
+// iterate steps it using step until it returns false, returning the keys, and checking each pair against model.
+func iterate(t testing.TB, it simhash.Iterator, step func() bool, model *mapModel) []simhash.Key {
+	t.Helper()
+	keys := make([]simhash.Key, 0, len(model.keys))
+	for true == step() {
+		pair := it.Pair()
+		if nil == pair {
+			t.Fatal("iterator returned a nil pair")
+		}
+		if false == keysEqual(pair.Key(), it.Key()) || pair.Value() != it.Value() {
+			t.Fatalf("iterator Key and Value are inconsistent with Pair")
+		}
+		if v, _ := model.get(it.Key()); v != it.Value() {
+			t.Fatalf("expected iterated key %s to have value %v, actual %v", describe(it.Key()), v, it.Value())
+		}
+		keys = append(keys, it.Key())
+		if len(keys) > len(model.keys) {
+			t.Fatalf("iterator returned too many pairs, %d", len(keys))
+		}
+	}
+	return keys
+}
+
+// verifyIterator checks that iterators visit every pair, in both directions, and can change direction.
+func verifyIterator(t testing.TB, m simhash.Map, model *mapModel) {
+	t.Helper()
+	it := m.Iterator()
+	if nil != it.Pair() || nil != it.Key() || nil != it.Value() {
+		t.Fatal("expected a new iterator to have no pair")
+	}
+	forwards := iterate(t, it, it.Next, model)
+	verifyDistinct(t, "Iterator.Next", forwards, model)
+	if len(forwards) != len(model.keys) {
+		t.Fatalf("expected Iterator.Next to return %d keys, actual %d", len(model.keys), len(forwards))
+	}
+	// after reaching the end, iterating backwards visits the same pairs in reverse
+	backwards := iterate(t, it, it.Previous, model)
+	if len(backwards) != len(forwards) {
+		t.Fatalf("expected Iterator.Previous to return %d keys, actual %d", len(forwards), len(backwards))
+	}
+	for i, key := range backwards {
+		if false == keysEqual(key, forwards[len(forwards)-1-i]) {
+			t.Fatalf("expected Iterator.Previous to return %v in reverse, actual %v", forwards, backwards)
+		}
+	}
+	// an iterator may start backwards
+	it = m.Iterator()
+	verifyDistinct(t, "Iterator.Previous", iterate(t, it, it.Previous, model), model)
+	// changing direction doesn't move, for the first step
+	if len(model.keys) < 2 {
+		return
+	}
+	it = m.Iterator()
+	if false == it.Next() || false == it.Next() {
+		t.Fatal("expected Iterator.Next to return true")
+	}
+	second := it.Key()
+	if false == it.Previous() || false == keysEqual(second, it.Key()) {
+		t.Fatal("expected the first Iterator.Previous after Iterator.Next to not move")
+	}
+	if false == it.Previous() || true == keysEqual(second, it.Key()) {
+		t.Fatal("expected the second Iterator.Previous after Iterator.Next to move")
+	}
+	first := it.Key()
+	if true == it.Previous() {
+		t.Fatal("expected Iterator.Previous to return false at the start")
+	}
+	if false == it.Next() || false == keysEqual(first, it.Key()) {
+		t.Fatal("expected Iterator.Next to restart at the first pair")
+	}
+}
+
+// checkEmpty checks a new map is empty.
+func checkEmpty(t testing.TB, m simhash.Map) {
+	t.Helper()
+	model := &mapModel{}
+	verifyMap(t, m, model)
+	if nil != m.Get(conformanceKey(1)) || true == m.Contains(conformanceKey(1)) || nil != m.Remove(conformanceKey(1)) {
+		t.Fatal("expected an empty map to contain no keys")
+	}
+	it := m.Iterator()
+	if true == it.Next() || true == it.Previous() || nil != it.Pair() {
+		t.Fatal("expected an empty iterator to return no pairs")
+	}
+}
+
+// checkOperations performs random operations on m, checking the results against a model, using a range of keys
+// which collide, along with nil keys, if enabled, and nil values.
+func checkOperations(t testing.TB, m simhash.Map, c *conformanceConfig) {
+	t.Helper()
+	model := &mapModel{}
+	r := rand.New(rand.NewSource(1))
+	key := func() simhash.Key {
+		if true == c.nilKeys && 0 == r.Intn(20) {
+			return nil
+		}
+		return conformanceKey(r.Intn(30))
+	}
+	value := func() simhash.Value {
+		if 0 == r.Intn(10) {
+			return nil
+		}
+		return r.Intn(100)
+	}
+	cm, compute := m.(simhash.ComputeMap)
+	for i := 0; i < 2000; i++ {
+		k := key()
+		expected, _ := model.get(k)
+		var actual simhash.Value
+		var op string
+		switch n := r.Intn(8); {
+		case n < 3:
+			op = "Put"
+			v := value()
+			actual = m.Put(k, v)
+			model.put(k, v)
+		case n < 5:
+			op = "Remove"
+			actual = m.Remove(k)
+			model.remove(k)
+		case n < 6:
+			op = "Get"
+			actual = m.Get(k)
+			if _, ok := model.get(k); ok != m.Contains(k) {
+				t.Fatalf("%d: expected Contains(%s) to be %t", i, describe(k), ok)
+			}
+		case false == compute:
+			continue
+		default:
+			op, expected, actual = checkCompute(r, cm, model, k, value())
+		}
+		if expected != actual {
+			t.Fatalf("%d: %s(%s): expected %v, actual %v", i, op, describe(k), expected, actual)
+		}
+		if size := m.Size(); len(model.keys) != size {
+			t.Fatalf("%d: %s(%s): expected size %d, actual %d", i, op, describe(k), len(model.keys), size)
+		}
+		if 0 == i%100 {
+			verifyMap(t, m, model)
+		}
+	}
+	verifyMap(t, m, model)
+}
+
+// checkCompute performs a random ComputeMap operation, returning it's name, and expected and actual result. The
+// functions passed to the map will return nil for nil values, removing the key.
+func checkCompute(r *rand.Rand, m simhash.ComputeMap, model *mapModel, k simhash.Key, v simhash.Value) (string, simhash.Value, simhash.Value) {
+	old, ok := model.get(k)
+	switch r.Intn(4) {
+	case 0:
+		actual := m.ComputeIfAbsent(k, func(simhash.Key) simhash.Value { return v })
+		if true == ok {
+			return "ComputeIfAbsent", old, actual
+		}
+		if nil != v {
+			model.put(k, v)
+		}
+		return "ComputeIfAbsent", v, actual
+	case 1:
+		actual := m.ComputeIfPresent(k, func(simhash.Key, simhash.Value) simhash.Value { return v })
+		if false == ok {
+			return "ComputeIfPresent", nil, actual
+		}
+		if nil == v {
+			model.remove(k)
+		} else {
+			model.put(k, v)
+		}
+		return "ComputeIfPresent", v, actual
+	case 2:
+		actual := m.Compute(k, func(simhash.Key, simhash.Value) simhash.Value { return v })
+		if nil == v {
+			model.remove(k)
+		} else {
+			model.put(k, v)
+		}
+		return "Compute", v, actual
+	default:
+		actual := m.Merge(k, v, func(a, b simhash.Value) simhash.Value {
+			if nil == a || nil == b {
+				return nil
+			}
+			return a.(int) + b.(int)
+		})
+		switch {
+		case false == ok:
+			if nil != v {
+				model.put(k, v)
+			}
+			return "Merge", v, actual
+		case nil == old || nil == v:
+			model.remove(k)
+			return "Merge", nil, actual
+		default:
+			model.put(k, old.(int)+v.(int))
+			return "Merge", old.(int) + v.(int), actual
+		}
+	}
+}
+
+// checkNilKeysAndValues checks that nil keys, and nil values, are distinct from missing keys.
+func checkNilKeysAndValues(t testing.TB, m simhash.Map, c *conformanceConfig) {
+	t.Helper()
+	model := &mapModel{}
+	if true == c.nilKeys {
+		if true == m.Contains(nil) || nil != m.Put(nil, 1) || true != m.Contains(nil) || 1 != m.Get(nil) {
+			t.Fatal("expected a nil key to be stored")
+		}
+		model.put(nil, 1)
+	}
+	if nil != m.Put(conformanceKey(1), nil) || true != m.Contains(conformanceKey(1)) || nil != m.Get(conformanceKey(1)) {
+		t.Fatal("expected a nil value to be stored")
+	}
+	model.put(conformanceKey(1), nil)
+	verifyMap(t, m, model)
+	if nil != m.Remove(conformanceKey(1)) || true == m.Contains(conformanceKey(1)) {
+		t.Fatal("expected a nil value to be removed")
+	}
+	model.remove(conformanceKey(1))
+	if true == c.nilKeys {
+		if 1 != m.Remove(nil) || true == m.Contains(nil) {
+			t.Fatal("expected a nil key to be removed")
+		}
+		model.remove(nil)
+	}
+	verifyMap(t, m, model)
+}
+
+// checkRemoveWhileIterating removes keys while iterating, which must not cause the iterator to return any pair more
+// than once, or return any pair after it was removed, though it may skip pairs. Fail-fast iterators may instead
+// panic with a *simhash.ConcurrentModificationError, on the step after the first removal.
+func checkRemoveWhileIterating(t testing.TB, m simhash.Map) {
+	t.Helper()
+	model := &mapModel{}
+	for i := 0; i < 60; i++ {
+		m.Put(conformanceKey(i), i)
+		model.put(conformanceKey(i), i)
+	}
+	seen := make(map[simhash.Key]bool)
+	removed := make(map[simhash.Key]bool)
+	it := m.Iterator()
+	next := func() (ok bool) {
+		defer func() {
+			if r := recover(); nil != r {
+				if _, failFast := r.(*simhash.ConcurrentModificationError); false == failFast || 0 == len(removed) {
+					panic(r)
+				}
+				ok = false
+			}
+		}()
+		return it.Next()
+	}
+	for true == next() {
+		k := it.Key()
+		if true == seen[k] {
+			t.Fatalf("iterator returned %s twice", describe(k))
+		}
+		if true == removed[k] {
+			t.Fatalf("iterator returned %s after it was removed", describe(k))
+		}
+		seen[k] = true
+		if 0 == k.(conformanceKey)%2 {
+			if v := m.Remove(k); v != model.remove(k) {
+				t.Fatalf("unexpected removed value %v", v)
+			}
+			removed[k] = true
+		}
+	}
+	verifyMap(t, m, model)
+}
+
+// checkMutableIterator removes and replaces every pair using a simhash.MutableIterator, if the map's iterators
+// implement it, in which case the iterator must still return every pair exactly once.
+func checkMutableIterator(t testing.TB, m simhash.Map) {
+	t.Helper()
+	model := &mapModel{}
+	for i := 0; i < 60; i++ {
+		m.Put(conformanceKey(i), i)
+		model.put(conformanceKey(i), i)
+	}
+	it, ok := m.Iterator().(simhash.MutableIterator)
+	if false == ok {
+		t.Skip("the iterator is not a simhash.MutableIterator")
+	}
+	seen := make(map[simhash.Key]bool)
+	for true == it.Next() {
+		k := it.Key()
+		if true == seen[k] {
+			t.Fatalf("iterator returned %s twice", describe(k))
+		}
+		seen[k] = true
+		if 0 == k.(conformanceKey)%3 {
+			if v := it.Remove(); v != model.remove(k) {
+				t.Fatalf("unexpected removed value %v", v)
+			}
+			if nil != it.Pair() {
+				t.Fatalf("expected no current pair after removing %s", describe(k))
+			}
+			continue
+		}
+		old, _ := model.get(k)
+		if v := it.SetValue(-old.(int)); v != old {
+			t.Fatalf("unexpected replaced value %v", v)
+		}
+		model.put(k, -old.(int))
+		if -old.(int) != it.Value() {
+			t.Fatalf("expected the value of %s to be replaced", describe(k))
+		}
+	}
+	if len(seen) != 60 {
+		t.Fatalf("expected the iterator to return 60 keys, actual %d", len(seen))
+	}
+	verifyMap(t, m, model)
+}
+
+// RunMapConformance runs subtests which check that the maps returned by newMap, which must be empty, conform to the
+// documented behaviour of simhash.Map, and simhash.ComputeMap, if they implement it, by comparing them with a simple
+// reference model. It checks every method of both the map, and it's iterators, including nil keys and values, keys
+// with colliding hashes, removal of the current key during iteration, simhash.MutableIterator, if it is implemented,
+// and Serialize, using keys that implement fmt.Stringer. The keys used also implement simhash.Comparable. Iterators
+// may be fail-fast, or weakly consistent.
+func RunMapConformance(t *testing.T, newMap func() simhash.Map, options ...ConformanceOption) {
+	t.Helper()
+	c := &conformanceConfig{nilKeys: true}
+	for _, option := range options {
+		option(c)
+	}
+	t.Run("Empty", func(t *testing.T) {
+		checkEmpty(t, newMap())
+	})
+	t.Run("Operations", func(t *testing.T) {
+		checkOperations(t, newMap(), c)
+	})
+	t.Run("NilKeysAndValues", func(t *testing.T) {
+		checkNilKeysAndValues(t, newMap(), c)
+	})
+	t.Run("RemoveWhileIterating", func(t *testing.T) {
+		checkRemoveWhileIterating(t, newMap())
+	})
+	t.Run("MutableIterator", func(t *testing.T) {
+		checkMutableIterator(t, newMap())
+	})
+}
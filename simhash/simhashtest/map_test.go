@@ -0,0 +1,210 @@
+/*
+   Copyright 2017 Joseph Cumines
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+
+ */
+
+package simhashtest
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/joeycumines/go-hashmap/simhash"
+)
+
+// fatal is used to stop a check, when the recorder fails.
+type fatal struct{}
+
+func (r *recorder) Fatal(args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprint(args...))
+	panic(fatal{})
+}
+
+func (r *recorder) Fatalf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+	panic(fatal{})
+}
+
+// record runs check, returning any errors, with the recorder stopping on the first fatal error.
+func record(check func(t testing.TB)) (errors []string) {
+	r := &recorder{}
+	defer func() {
+		if v := recover(); nil != v {
+			if _, ok := v.(fatal); false == ok {
+				panic(v)
+			}
+		}
+		errors = r.errors
+	}()
+	check(r)
+	return
+}
+
+func TestRunMapConformance(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		newMap  func() simhash.Map
+		options []ConformanceOption
+	}{
+		{"NewMap", func() simhash.Map { return simhash.NewMap() }, nil},
+		{"NewMap open addressing", func() simhash.Map { return simhash.NewMap(simhash.WithOpenAddressing()) }, nil},
+		{"NewMap weakly consistent", func() simhash.Map { return simhash.NewMap(simhash.WithWeaklyConsistentIterators()) }, nil},
+		{"NewMap unseeded", func() simhash.Map { return simhash.NewMap(simhash.WithoutHashSeed()) }, nil},
+		{"NewConcurrentMap", func() simhash.Map { return simhash.NewConcurrentMap() }, nil},
+		{"NewConcurrentMap open addressing", func() simhash.Map { return simhash.NewConcurrentMap(simhash.WithOpenAddressing()) }, nil},
+		{"NewLinkedMap", simhash.NewLinkedMap, nil},
+		{"NewAccessOrderedLinkedMap", simhash.NewAccessOrderedLinkedMap, nil},
+		{"NewLRU", func() simhash.Map { return simhash.NewLRU(1000, nil) }, nil},
+		{"NewSortedMap", func() simhash.Map { return simhash.NewSortedMap() }, []ConformanceOption{WithoutNilKeys()}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			RunMapConformance(t, tc.newMap, tc.options...)
+		})
+	}
+}
+
+// brokenMap wraps a map, allowing methods to be replaced, to break it.
+type brokenMap struct {
+	simhash.Map
+	put      func(key simhash.Key, value simhash.Value) simhash.Value
+	size     func() int
+	iterator func() simhash.Iterator
+}
+
+func (m *brokenMap) Put(key simhash.Key, value simhash.Value) simhash.Value {
+	if nil != m.put {
+		return m.put(key, value)
+	}
+	return m.Map.Put(key, value)
+}
+
+func (m *brokenMap) Size() int {
+	if nil != m.size {
+		return m.size()
+	}
+	return m.Map.Size()
+}
+
+func (m *brokenMap) Iterator() simhash.Iterator {
+	if nil != m.iterator {
+		return m.iterator()
+	}
+	return m.Map.Iterator()
+}
+
+// reversingIterator moves when it changes direction.
+type reversingIterator struct {
+	simhash.Iterator
+	forwards bool
+}
+
+func (it *reversingIterator) Previous() bool {
+	if true == it.forwards {
+		it.forwards = false
+		it.Iterator.Previous()
+	}
+	return it.Iterator.Previous()
+}
+
+func (it *reversingIterator) Next() bool {
+	it.forwards = true
+	return it.Iterator.Next()
+}
+
+func TestRunMapConformance_broken(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		check    func(t testing.TB, m simhash.Map)
+		broken   func(m *brokenMap)
+		expected string
+	}{
+		{
+			"wrong size",
+			checkEmpty,
+			func(m *brokenMap) {
+				m.size = func() int { return 1 }
+			},
+			"expected size 0, actual 1",
+		},
+		{
+			"nil values dropped",
+			func(t testing.TB, m simhash.Map) {
+				checkNilKeysAndValues(t, m, &conformanceConfig{})
+			},
+			func(m *brokenMap) {
+				m.put = func(key simhash.Key, value simhash.Value) simhash.Value {
+					if nil == value {
+						return m.Map.Remove(key)
+					}
+					return m.Map.Put(key, value)
+				}
+			},
+			"expected a nil value to be stored",
+		},
+		{
+			"nil keys unsupported",
+			func(t testing.TB, m simhash.Map) {
+				checkNilKeysAndValues(t, m, &conformanceConfig{nilKeys: true})
+			},
+			func(m *brokenMap) {
+				m.Map = simhash.NewSortedMap()
+			},
+			"panicked",
+		},
+		{
+			"put returns the new value",
+			func(t testing.TB, m simhash.Map) {
+				checkOperations(t, m, &conformanceConfig{})
+			},
+			func(m *brokenMap) {
+				m.put = func(key simhash.Key, value simhash.Value) simhash.Value {
+					m.Map.Put(key, value)
+					return value
+				}
+			},
+			"Put(k",
+		},
+		{
+			"iterator moves when reversing",
+			checkRemoveWhileIterating,
+			func(m *brokenMap) {
+				m.iterator = func() simhash.Iterator {
+					return &reversingIterator{Iterator: m.Map.Iterator()}
+				}
+			},
+			"expected Iterator.Previous to return",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			m := &brokenMap{Map: simhash.NewMap()}
+			tc.broken(m)
+			errors := record(func(t testing.TB) {
+				defer func() {
+					if v := recover(); nil != v {
+						if _, ok := v.(fatal); true == ok {
+							panic(v)
+						}
+						t.Fatalf("panicked: %v", v)
+					}
+				}()
+				tc.check(t, m)
+			})
+			if 1 != len(errors) || false == strings.Contains(errors[0], tc.expected) {
+				t.Fatalf("unexpected errors: %q", errors)
+			}
+		})
+	}
+}
@@ -0,0 +1,254 @@
+/*
+   Copyright 2017 Joseph Cumines
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+
+ */
+
+package simhash
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Comparable is a Key that also has a natural ordering, the same as Comparable in Java, which must be consistent
+// with Equals, that is a.Compare(b) must return 0 if and only if a.Equals(b).
+type Comparable interface {
+	Key
+
+	// Compare must return a negative number, zero, or a positive number, if this key is less than, equal to, or
+	// greater than other, respectively.
+	Compare(other interface{}) int
+}
+
+// SortedMap is a Map that keeps it's keys sorted, using their natural ordering, the same as Java's NavigableMap.
+// Keys, Values, Pairs, Serialize and Iterator will all operate in ascending key order.
+// All keys must implement Comparable, and must not be nil. Implementations will panic if any other key is stored,
+// and will treat any other key as not existing, including as an argument to the navigation methods, where a nil key
+// has no neighbours, and bounds no pairs.
+type SortedMap interface {
+	Map
+
+	// FirstKey returns the lowest key in the map, or nil if it is empty.
+	FirstKey() Key
+
+	// LastKey returns the highest key in the map, or nil if it is empty.
+	LastKey() Key
+
+	// Floor returns the pair with the greatest key less than or equal to key, or nil if there is no such key.
+	Floor(key Comparable) Pair
+
+	// Ceiling returns the pair with the least key greater than or equal to key, or nil if there is no such key.
+	Ceiling(key Comparable) Pair
+
+	// Higher returns the pair with the least key strictly greater than key, or nil if there is no such key.
+	Higher(key Comparable) Pair
+
+	// Lower returns the pair with the greatest key strictly less than key, or nil if there is no such key.
+	Lower(key Comparable) Pair
+
+	// HeadMap returns a new SortedMap containing the pairs with keys strictly less than to.
+	HeadMap(to Comparable) SortedMap
+
+	// TailMap returns a new SortedMap containing the pairs with keys greater than or equal to from.
+	TailMap(from Comparable) SortedMap
+
+	// SubMap returns a new SortedMap containing the pairs with keys greater than or equal to from, and strictly less
+	// than to.
+	SubMap(from, to Comparable) SortedMap
+}
+
+// sortedMap implements SortedMap using an avlTree, it has the same concurrency restrictions as hashMap.
+// The maps returned by HeadMap, TailMap and SubMap are copies, and are independent of the original.
+type sortedMap struct {
+	t *avlTree
+}
+
+func compareKeys(a, b Key) int {
+	return a.(Comparable).Compare(b)
+}
+
+func isComparable(key Key) bool {
+	_, ok := key.(Comparable)
+	return ok
+}
+
+// pairOf converts a node to a Pair, as the nodes themselves are mutable.
+func pairOf(n *avlNode) Pair {
+	if nil == n {
+		return nil
+	}
+	return NewPair(n.key, n.value)
+}
+
+func (m *sortedMap) Contains(key Key) bool {
+	return true == isComparable(key) && nil != m.t.get(key)
+}
+
+func (m *sortedMap) Get(key Key) Value {
+	if false == isComparable(key) {
+		return nil
+	}
+	if n := m.t.get(key); nil != n {
+		return n.value
+	}
+	return nil
+}
+
+func (m *sortedMap) Put(key Key, value Value) Value {
+	if false == isComparable(key) {
+		panic(errors.New("the keys of a sorted map must implement Comparable"))
+	}
+	v, _ := m.t.put(key, value)
+	return v
+}
+
+func (m *sortedMap) Remove(key Key) Value {
+	if false == isComparable(key) {
+		return nil
+	}
+	v, _ := m.t.remove(key)
+	return v
+}
+
+func (m *sortedMap) Keys() []Key {
+	keys := make([]Key, 0, m.t.size)
+	m.t.walk(func(n *avlNode) bool {
+		keys = append(keys, n.key)
+		return true
+	})
+	return keys
+}
+
+func (m *sortedMap) Values() []Value {
+	values := make([]Value, 0, m.t.size)
+	m.t.walk(func(n *avlNode) bool {
+		values = append(values, n.value)
+		return true
+	})
+	return values
+}
+
+func (m *sortedMap) Pairs() []Pair {
+	pairs := make([]Pair, 0, m.t.size)
+	m.t.walk(func(n *avlNode) bool {
+		pairs = append(pairs, pairOf(n))
+		return true
+	})
+	return pairs
+}
+
+func (m *sortedMap) Size() int {
+	return m.t.size
+}
+
+func (m *sortedMap) Serialize() map[string]interface{} {
+	serialized := make(map[string]interface{})
+	m.t.walk(func(n *avlNode) bool {
+		kStringer, ok := n.key.(fmt.Stringer)
+		var k string
+		if true == ok {
+			k = kStringer.String()
+		} else {
+			k = fmt.Sprintf("%v", n.key)
+		}
+		serialized[k] = n.value
+		return true
+	})
+	return serialized
+}
+
+// Iterator returns an iterator over a snapshot of the pairs in the map, in ascending key order, where Remove and
+// SetValue are the same as calling Remove or Put on the map.
+func (m *sortedMap) Iterator() Iterator {
+	return newSnapshotIterator(m, m.Pairs())
+}
+
+func (m *sortedMap) FirstKey() Key {
+	if n := m.t.first(); nil != n {
+		return n.key
+	}
+	return nil
+}
+
+func (m *sortedMap) LastKey() Key {
+	if n := m.t.last(); nil != n {
+		return n.key
+	}
+	return nil
+}
+
+func (m *sortedMap) Floor(key Comparable) Pair {
+	if nil == key {
+		return nil
+	}
+	return pairOf(m.t.floor(key))
+}
+
+func (m *sortedMap) Ceiling(key Comparable) Pair {
+	if nil == key {
+		return nil
+	}
+	return pairOf(m.t.ceiling(key))
+}
+
+func (m *sortedMap) Higher(key Comparable) Pair {
+	if nil == key {
+		return nil
+	}
+	return pairOf(m.t.higher(key))
+}
+
+func (m *sortedMap) Lower(key Comparable) Pair {
+	if nil == key {
+		return nil
+	}
+	return pairOf(m.t.lower(key))
+}
+
+// subMap copies the range [from, to) into a new map, where a nil bound is unbounded.
+func (m *sortedMap) subMap(from, to Key) SortedMap {
+	sub := NewSortedMap().(*sortedMap)
+	m.t.walkRange(from, to, func(n *avlNode) bool {
+		sub.t.put(n.key, n.value)
+		return true
+	})
+	return sub
+}
+
+func (m *sortedMap) HeadMap(to Comparable) SortedMap {
+	if nil == to {
+		return NewSortedMap()
+	}
+	return m.subMap(nil, to)
+}
+
+func (m *sortedMap) TailMap(from Comparable) SortedMap {
+	if nil == from {
+		return NewSortedMap()
+	}
+	return m.subMap(from, nil)
+}
+
+func (m *sortedMap) SubMap(from, to Comparable) SortedMap {
+	if nil == from || nil == to {
+		return NewSortedMap()
+	}
+	return m.subMap(from, to)
+}
+
+// NewSortedMap creates a new, empty, SortedMap, backed by a balanced binary tree. It is not safe for concurrent use.
+func NewSortedMap() SortedMap {
+	return &sortedMap{newAvlTree(compareKeys)}
+}
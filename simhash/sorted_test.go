@@ -0,0 +1,149 @@
+/*
+   Copyright 2017 Joseph Cumines
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+
+ */
+
+package simhash
+
+import (
+	"testing"
+)
+
+func genTestSortedMap() SortedMap {
+	m := NewSortedMap()
+	for _, k := range []int{50, 20, 40, 10, 30} {
+		m.Put(testKeyInt(k), k)
+	}
+	return m
+}
+
+func TestSortedMap_Put_panic(t *testing.T) {
+	defer func() {
+		err := recover().(error)
+		if nil == err || "the keys of a sorted map must implement Comparable" != err.Error() {
+			t.Fatal()
+		}
+	}()
+	NewSortedMap().Put(testKeyStruct{1, 1}, 1)
+}
+
+func TestSortedMap_nonComparable(t *testing.T) {
+	m := genTestSortedMap()
+	if true == m.Contains(nil) || nil != m.Get(nil) || nil != m.Remove(nil) ||
+		true == m.Contains(testKeyStruct{1, 1}) || nil != m.Get(testKeyStruct{1, 1}) || nil != m.Remove(testKeyStruct{1, 1}) ||
+		5 != m.Size() {
+		t.Fatal()
+	}
+	// navigation treats nil keys as not existing, the same as Get
+	if nil != m.Floor(nil) || nil != m.Ceiling(nil) || nil != m.Higher(nil) || nil != m.Lower(nil) ||
+		0 != m.HeadMap(nil).Size() || 0 != m.TailMap(nil).Size() || 0 != m.SubMap(nil, testKeyInt(30)).Size() ||
+		0 != m.SubMap(testKeyInt(10), nil).Size() {
+		t.Fatal()
+	}
+}
+
+func TestSortedMap(t *testing.T) {
+	m := genTestSortedMap()
+	if 5 != m.Size() || 30 != m.Get(testKeyInt(30)).(int) || true != m.Contains(testKeyInt(30)) {
+		t.Fatal()
+	}
+	if 30 != m.Put(testKeyInt(30), 33).(int) || 33 != m.Get(testKeyInt(30)).(int) || 5 != m.Size() {
+		t.Fatal()
+	}
+	if 33 != m.Remove(testKeyInt(30)).(int) || nil != m.Remove(testKeyInt(30)) || 4 != m.Size() {
+		t.Fatal()
+	}
+	m.Put(testKeyInt(30), 30)
+	keys := make([]int, 0)
+	for _, k := range m.Keys() {
+		keys = append(keys, int(k.(testKeyInt)))
+	}
+	values := make([]int, 0)
+	for _, v := range m.Values() {
+		values = append(values, v.(int))
+	}
+	pairs := make([]int, 0)
+	for _, p := range m.Pairs() {
+		if int(p.Key().(testKeyInt)) != p.Value().(int) {
+			t.Fatal()
+		}
+		pairs = append(pairs, p.Value().(int))
+	}
+	expected := []int{10, 20, 30, 40, 50}
+	if false == equalInts(expected, keys) || false == equalInts(expected, values) || false == equalInts(expected, pairs) {
+		t.Fatal(keys, values, pairs)
+	}
+	if s := m.Serialize(); 5 != len(s) || 10 != s["10"].(int) || 50 != s["50"].(int) {
+		t.Fatal(s)
+	}
+}
+
+func TestSortedMap_Iterator(t *testing.T) {
+	m := genTestSortedMap()
+	it := m.Iterator()
+	values := make([]int, 0)
+	for true == it.Next() {
+		values = append(values, it.Value().(int))
+	}
+	for true == it.Previous() {
+		values = append(values, it.Value().(int))
+	}
+	if false == equalInts([]int{10, 20, 30, 40, 50, 50, 40, 30, 20, 10}, values) {
+		t.Fatal(values)
+	}
+}
+
+func TestSortedMap_navigation(t *testing.T) {
+	m := genTestSortedMap()
+	if 10 != int(m.FirstKey().(testKeyInt)) || 50 != int(m.LastKey().(testKeyInt)) {
+		t.Fatal()
+	}
+	if nil != NewSortedMap().FirstKey() || nil != NewSortedMap().LastKey() {
+		t.Fatal()
+	}
+	if 20 != m.Floor(testKeyInt(25)).Value().(int) || 30 != m.Ceiling(testKeyInt(25)).Value().(int) ||
+		30 != m.Higher(testKeyInt(20)).Value().(int) || 10 != m.Lower(testKeyInt(20)).Value().(int) {
+		t.Fatal()
+	}
+	if nil != m.Floor(testKeyInt(5)) || nil != m.Ceiling(testKeyInt(55)) || nil != m.Higher(testKeyInt(50)) || nil != m.Lower(testKeyInt(10)) {
+		t.Fatal()
+	}
+}
+
+func TestSortedMap_subMaps(t *testing.T) {
+	m := genTestSortedMap()
+	values := func(m Map) []int {
+		values := make([]int, 0)
+		for _, v := range m.Values() {
+			values = append(values, v.(int))
+		}
+		return values
+	}
+	head := m.HeadMap(testKeyInt(30))
+	tail := m.TailMap(testKeyInt(30))
+	sub := m.SubMap(testKeyInt(15), testKeyInt(40))
+	if false == equalInts([]int{10, 20}, values(head)) ||
+		false == equalInts([]int{30, 40, 50}, values(tail)) ||
+		false == equalInts([]int{20, 30}, values(sub)) ||
+		0 != m.SubMap(testKeyInt(30), testKeyInt(30)).Size() {
+		t.Fatal(values(head), values(tail), values(sub))
+	}
+	// they are copies
+	sub.Put(testKeyInt(60), 60)
+	head.Remove(testKeyInt(10))
+	if 5 != m.Size() || true == m.Contains(testKeyInt(60)) || false == m.Contains(testKeyInt(10)) {
+		t.Fatal()
+	}
+}
@@ -0,0 +1,383 @@
+/*
+   Copyright 2017 Joseph Cumines
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+
+ */
+
+package simhash
+
+import (
+	"math/bits"
+
+	"github.com/joeycumines/go-hashmap/simhash/internal/hashbits"
+)
+
+const (
+	swissGroupSize = 8
+	// control bytes for slots that are not full, full slots store the low 7 bits of the hash (h2)
+	swissEmpty   = 0x80
+	swissDeleted = 0xFE
+	// bit patterns with a bit set in each byte of a control word
+	swissLsbs = 0x0101010101010101
+	swissMsbs = 0x8080808080808080
+)
+
+// swissGroup is a group of slots, with their control bytes packed into a single word, so that they can be matched
+// in parallel, using portable bit manipulation rather than SIMD instructions.
+type swissGroup struct {
+	ctrl   uint64
+	keys   [swissGroupSize]Key
+	values [swissGroupSize]Value
+}
+
+// swissMatch is a bitmask with the high bit of each matching byte set.
+type swissMatch uint64
+
+// next returns the index of the first match, and the remaining matches.
+func (m swissMatch) next() (int, swissMatch) {
+	return bits.TrailingZeros64(uint64(m)) / 8, m & (m - 1)
+}
+
+// matchH2 returns the slots which may contain a key with the given h2, which may include false positives.
+func (g *swissGroup) matchH2(h2 uint8) swissMatch {
+	x := g.ctrl ^ (swissLsbs * uint64(h2))
+	return swissMatch((x - swissLsbs) &^ x & swissMsbs)
+}
+
+func (g *swissGroup) matchEmpty() swissMatch {
+	return swissMatch(g.ctrl &^ (g.ctrl << 6) & swissMsbs)
+}
+
+func (g *swissGroup) matchEmptyOrDeleted() swissMatch {
+	return swissMatch(g.ctrl & swissMsbs)
+}
+
+func (g *swissGroup) setCtrl(i int, c uint8) {
+	shift := uint(i) * 8
+	g.ctrl = g.ctrl&^(0xFF<<shift) | uint64(c)<<shift
+}
+
+func (g *swissGroup) isFull(i int) bool {
+	return 0 == g.ctrl>>(uint(i)*8)&0x80
+}
+
+// swissMap is an open addressing Map, which probes groups of slots using quadratic (triangular) probing, and is
+// resized to keep at most 7/8 of the slots in use, where removed slots become tombstones unless they can be safely
+// marked empty. It has the same concurrency restrictions as hashMap.
+type swissMap struct {
+	groups     []swissGroup
+	size       int
+	tombstones int
+	seed       uint64
+	// modCount is incremented whenever a key is added or removed, see hashMap.
+	modCount      int
+	weakIterators bool
+}
+
+// hash mixes the hash of key with the seed of the map, using hashbits.Mix, as the low bits are used for probing.
+func (m *swissMap) hash(key Key) uint64 {
+	return hashbits.Mix(hash64Of(key) ^ m.seed)
+}
+
+// find returns the group and slot of key.
+func (m *swissMap) find(h uint64, key Key) (int, int, bool) {
+	if 0 == len(m.groups) {
+		return 0, 0, false
+	}
+	mask := len(m.groups) - 1
+	g := int(h>>7) & mask
+	for step := 1; ; step++ {
+		group := &m.groups[g]
+		for match := group.matchH2(uint8(h & 0x7F)); 0 != match; {
+			var i int
+			i, match = match.next()
+			if true == group.isFull(i) && true == keysEqual(key, group.keys[i]) {
+				return g, i, true
+			}
+		}
+		if 0 != group.matchEmpty() {
+			return 0, 0, false
+		}
+		g = (g + step) & mask
+	}
+}
+
+// insert stores a key that does not exist in the map, resizing first if necessary.
+func (m *swissMap) insert(h uint64, key Key, value Value) {
+	if (m.size+m.tombstones+1)*8 > len(m.groups)*swissGroupSize*7 {
+		m.rehash()
+	}
+	mask := len(m.groups) - 1
+	g := int(h>>7) & mask
+	for step := 1; ; step++ {
+		group := &m.groups[g]
+		if match := group.matchEmptyOrDeleted(); 0 != match {
+			i, _ := match.next()
+			if false == group.isEmpty(i) {
+				m.tombstones--
+			}
+			group.setCtrl(i, uint8(h&0x7F))
+			group.keys[i] = key
+			group.values[i] = value
+			m.size++
+			m.modCount++
+			return
+		}
+		g = (g + step) & mask
+	}
+}
+
+func (g *swissGroup) isEmpty(i int) bool {
+	return swissEmpty == uint8(g.ctrl>>(uint(i)*8))
+}
+
+// removeAt removes the key in slot i of group g, and returns it's value.
+func (m *swissMap) removeAt(g, i int) Value {
+	group := &m.groups[g]
+	v := group.values[i]
+	group.keys[i] = nil
+	group.values[i] = nil
+	// if the group has an empty slot, no probe sequence can have continued past it, so the slot can be reused
+	if 0 != group.matchEmpty() {
+		group.setCtrl(i, swissEmpty)
+	} else {
+		group.setCtrl(i, swissDeleted)
+		m.tombstones++
+	}
+	m.size--
+	m.modCount++
+	return v
+}
+
+// rehash resizes the map so that it is at most half full, which will also clear any tombstones.
+func (m *swissMap) rehash() {
+	groups := 1
+	for groups*swissGroupSize < (m.size+1)*2 {
+		groups <<= 1
+	}
+	old := m.groups
+	m.groups = make([]swissGroup, groups)
+	for g := range m.groups {
+		m.groups[g].ctrl = swissLsbs * swissEmpty
+	}
+	m.size = 0
+	m.tombstones = 0
+	for g := range old {
+		for i := 0; i < swissGroupSize; i++ {
+			if true == old[g].isFull(i) {
+				m.insert(m.hash(old[g].keys[i]), old[g].keys[i], old[g].values[i])
+			}
+		}
+	}
+}
+
+func (m *swissMap) Contains(key Key) bool {
+	_, _, ok := m.find(m.hash(key), key)
+	return ok
+}
+
+func (m *swissMap) Get(key Key) Value {
+	g, i, ok := m.find(m.hash(key), key)
+	if false == ok {
+		return nil
+	}
+	return m.groups[g].values[i]
+}
+
+func (m *swissMap) Put(key Key, value Value) Value {
+	h := m.hash(key)
+	if g, i, ok := m.find(h, key); true == ok {
+		v := m.groups[g].values[i]
+		m.groups[g].keys[i] = key
+		m.groups[g].values[i] = value
+		return v
+	}
+	m.insert(h, key, value)
+	return nil
+}
+
+func (m *swissMap) Remove(key Key) Value {
+	g, i, ok := m.find(m.hash(key), key)
+	if false == ok {
+		return nil
+	}
+	return m.removeAt(g, i)
+}
+
+func (m *swissMap) ComputeIfAbsent(key Key, fn func(key Key) Value) Value {
+	h := m.hash(key)
+	if g, i, ok := m.find(h, key); true == ok {
+		return m.groups[g].values[i]
+	}
+	value := fn(key)
+	if nil != value {
+		m.insert(h, key, value)
+	}
+	return value
+}
+
+func (m *swissMap) ComputeIfPresent(key Key, fn func(key Key, value Value) Value) Value {
+	g, i, ok := m.find(m.hash(key), key)
+	if false == ok {
+		return nil
+	}
+	value := fn(key, m.groups[g].values[i])
+	if nil == value {
+		m.removeAt(g, i)
+		return nil
+	}
+	m.groups[g].keys[i] = key
+	m.groups[g].values[i] = value
+	return value
+}
+
+func (m *swissMap) Compute(key Key, fn func(key Key, value Value) Value) Value {
+	h := m.hash(key)
+	g, i, ok := m.find(h, key)
+	var old Value
+	if true == ok {
+		old = m.groups[g].values[i]
+	}
+	value := fn(key, old)
+	switch {
+	case nil == value && true == ok:
+		m.removeAt(g, i)
+	case nil == value:
+	case true == ok:
+		m.groups[g].keys[i] = key
+		m.groups[g].values[i] = value
+	default:
+		m.insert(h, key, value)
+	}
+	return value
+}
+
+func (m *swissMap) Merge(key Key, value Value, fn func(old, new Value) Value) Value {
+	h := m.hash(key)
+	g, i, ok := m.find(h, key)
+	if false == ok {
+		if nil != value {
+			m.insert(h, key, value)
+		}
+		return value
+	}
+	value = fn(m.groups[g].values[i], value)
+	if nil == value {
+		m.removeAt(g, i)
+		return nil
+	}
+	m.groups[g].keys[i] = key
+	m.groups[g].values[i] = value
+	return value
+}
+
+// walk calls fn with the group and slot of each key in the map.
+func (m *swissMap) walk(fn func(group *swissGroup, i int)) {
+	for g := range m.groups {
+		group := &m.groups[g]
+		for i := 0; i < swissGroupSize; i++ {
+			if true == group.isFull(i) {
+				fn(group, i)
+			}
+		}
+	}
+}
+
+func (m *swissMap) Keys() []Key {
+	keys := make([]Key, 0, m.size)
+	m.walk(func(group *swissGroup, i int) {
+		keys = append(keys, group.keys[i])
+	})
+	return keys
+}
+
+func (m *swissMap) Values() []Value {
+	values := make([]Value, 0, m.size)
+	m.walk(func(group *swissGroup, i int) {
+		values = append(values, group.values[i])
+	})
+	return values
+}
+
+func (m *swissMap) Pairs() []Pair {
+	pairs := make([]Pair, 0, m.size)
+	m.walk(func(group *swissGroup, i int) {
+		pairs = append(pairs, NewPair(group.keys[i], group.values[i]))
+	})
+	return pairs
+}
+
+func (m *swissMap) Size() int {
+	return m.size
+}
+
+func (m *swissMap) Serialize() map[string]interface{} {
+	serialized := make(map[string]interface{})
+	m.walk(func(group *swissGroup, i int) {
+		serialized[serializeKey(group.keys[i])] = group.values[i]
+	})
+	return serialized
+}
+
+// Iterator returns a fail-fast iterator that walks the slots of the map directly, which would otherwise skip or
+// repeat pairs if the map is resized during iteration. Removing a key never moves the other keys.
+func (m *swissMap) Iterator() Iterator {
+	modCount := m.modCount
+	it := &sliceIterator{
+		check: func() {
+			if false == m.weakIterators && modCount != m.modCount {
+				panic(&ConcurrentModificationError{})
+			}
+		},
+		length: func() int {
+			return len(m.groups) * swissGroupSize
+		},
+		at: func(x int) Pair {
+			group := &m.groups[x/swissGroupSize]
+			i := x % swissGroupSize
+			if false == group.isFull(i) {
+				return nil
+			}
+			return NewPair(group.keys[i], group.values[i])
+		},
+		forwards: true,
+	}
+	return &mutableSliceIterator{
+		sliceIterator: it,
+		remove: func(key Key) Value {
+			g, i, ok := m.find(m.hash(key), key)
+			if false == ok {
+				panic(errNoCurrentPair)
+			}
+			v := m.removeAt(g, i)
+			modCount = m.modCount
+			return v
+		},
+		setValue: func(key Key, value Value) Value {
+			g, i, ok := m.find(m.hash(key), key)
+			if false == ok {
+				panic(errNoCurrentPair)
+			}
+			v := m.groups[g].values[i]
+			m.groups[g].values[i] = value
+			return v
+		},
+	}
+}
+
+func newSwissMap() *swissMap {
+	m := &swissMap{}
+	m.rehash()
+	return m
+}
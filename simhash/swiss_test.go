@@ -0,0 +1,265 @@
+/*
+   Copyright 2017 Joseph Cumines
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+
+ */
+
+package simhash
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func TestNewMap_WithOpenAddressing(t *testing.T) {
+	m, ok := NewMap(WithOpenAddressing()).(*swissMap)
+	if false == ok || 1 != len(m.groups) || 0 != m.size || swissLsbs*swissEmpty != m.groups[0].ctrl {
+		t.Fatal()
+	}
+	var _ ComputeMap = m
+}
+
+func TestSwissGroup_match(t *testing.T) {
+	g := &swissGroup{ctrl: swissLsbs * swissEmpty}
+	g.setCtrl(1, 0x12)
+	g.setCtrl(3, swissDeleted)
+	g.setCtrl(6, 0x12)
+	g.setCtrl(7, 0x52)
+	indexes := func(m swissMatch) []int {
+		result := make([]int, 0)
+		for 0 != m {
+			var i int
+			i, m = m.next()
+			result = append(result, i)
+		}
+		return result
+	}
+	if false == equalInts([]int{1, 6}, indexes(g.matchH2(0x12))) {
+		t.Fatal(indexes(g.matchH2(0x12)))
+	}
+	if false == equalInts([]int{0, 2, 4, 5}, indexes(g.matchEmpty())) {
+		t.Fatal(indexes(g.matchEmpty()))
+	}
+	if false == equalInts([]int{0, 2, 3, 4, 5}, indexes(g.matchEmptyOrDeleted())) {
+		t.Fatal(indexes(g.matchEmptyOrDeleted()))
+	}
+	// false positives are possible, for a byte that differs from h2 by only the lowest bit, following a match
+	g.setCtrl(7, 0x13)
+	if false == equalInts([]int{1, 6, 7}, indexes(g.matchH2(0x12))) {
+		t.Fatal(indexes(g.matchH2(0x12)))
+	}
+	if true != g.isFull(1) || true == g.isFull(3) || true == g.isFull(0) || true != g.isEmpty(0) || true == g.isEmpty(3) {
+		t.Fatal()
+	}
+}
+
+func TestSwissMap(t *testing.T) {
+	m := NewMap(WithOpenAddressing())
+	if nil != m.Put(testKeyInt(67), "67") || 1 != m.Size() || "67" != m.Get(testKeyInt(67)).(string) {
+		t.Fatal()
+	}
+	if "67" != m.Put(testKeyInt(67), "sixty seven").(string) || 1 != m.Size() || "sixty seven" != m.Get(testKeyInt(67)).(string) {
+		t.Fatal()
+	}
+	if nil != m.Put(nil, "nil") || 2 != m.Size() || "nil" != m.Get(nil).(string) || true != m.Contains(nil) {
+		t.Fatal()
+	}
+	if nil != m.Remove(testKeyInt(68)) || "nil" != m.Remove(nil).(string) || true == m.Contains(nil) || 1 != m.Size() {
+		t.Fatal()
+	}
+	if s := m.Serialize(); 1 != len(s) || "sixty seven" != s["67"].(string) {
+		t.Fatal(s)
+	}
+}
+
+func TestSwissMap_random(t *testing.T) {
+	r := rand.New(rand.NewSource(3))
+	m := NewMap(WithOpenAddressing()).(*swissMap)
+	model := make(map[testKeyStruct]int)
+	for x := 0; x < 20000; x++ {
+		// lots of colliding hashes, and enough churn to create tombstones
+		k := testKeyStruct{r.Intn(200), r.Intn(10)}
+		switch r.Intn(3) {
+		case 0:
+			v := m.Remove(k)
+			if mv, ok := model[k]; (true == ok && mv != v.(int)) || (false == ok && nil != v) {
+				t.Fatal(k)
+			}
+			delete(model, k)
+		default:
+			v := m.Put(k, x)
+			if mv, ok := model[k]; (true == ok && mv != v.(int)) || (false == ok && nil != v) {
+				t.Fatal(k)
+			}
+			model[k] = x
+		}
+		if len(model) != m.Size() {
+			t.Fatal(len(model), m.Size())
+		}
+		if (m.size+m.tombstones)*8 > len(m.groups)*swissGroupSize*7 {
+			t.Fatal("over loaded")
+		}
+	}
+	for k, v := range model {
+		if v != m.Get(k).(int) {
+			t.Fatal(k)
+		}
+	}
+	pairs := m.Pairs()
+	if len(model) != len(pairs) || len(model) != len(m.Keys()) || len(model) != len(m.Values()) {
+		t.Fatal()
+	}
+	for _, pair := range pairs {
+		if model[pair.Key().(testKeyStruct)] != pair.Value().(int) {
+			t.Fatal(pair)
+		}
+	}
+}
+
+func TestSwissMap_compute(t *testing.T) {
+	m := NewMap(WithOpenAddressing()).(ComputeMap)
+	m.ComputeIfAbsent(testKeyInt(1), func(key Key) Value { return 1 })
+	m.ComputeIfAbsent(testKeyInt(1), func(key Key) Value { return 2 })
+	m.Compute(testKeyInt(2), func(key Key, value Value) Value { return 2 })
+	m.Merge(testKeyInt(2), 2, func(old, new Value) Value { return old.(int) + new.(int) })
+	m.Merge(testKeyInt(3), 3, nil)
+	m.ComputeIfPresent(testKeyInt(3), func(key Key, value Value) Value { return nil })
+	m.Compute(testKeyInt(1), func(key Key, value Value) Value { return value.(int) * 10 })
+	if 2 != m.Size() || 10 != m.Get(testKeyInt(1)).(int) || 4 != m.Get(testKeyInt(2)).(int) || true == m.Contains(testKeyInt(3)) {
+		t.Fatal()
+	}
+	m.Merge(testKeyInt(2), 2, func(old, new Value) Value { return nil })
+	m.Compute(testKeyInt(1), func(key Key, value Value) Value { return nil })
+	if 0 != m.Size() {
+		t.Fatal()
+	}
+}
+
+func TestSwissMap_Iterator(t *testing.T) {
+	m := NewMap(WithOpenAddressing())
+	for i := 0; i < 20; i++ {
+		m.Put(testKeyInt(i), i)
+	}
+	it := m.Iterator()
+	forwards := make([]int, 0)
+	for true == it.Next() {
+		forwards = append(forwards, it.Value().(int))
+	}
+	backwards := make([]int, 0)
+	for true == it.Previous() {
+		backwards = append(backwards, it.Value().(int))
+	}
+	if 20 != len(forwards) || 20 != len(backwards) {
+		t.Fatal(forwards, backwards)
+	}
+	for i := range forwards {
+		if forwards[i] != backwards[len(backwards)-1-i] {
+			t.Fatal(forwards, backwards)
+		}
+	}
+	sort.Ints(forwards)
+	for i, v := range forwards {
+		if i != v {
+			t.Fatal(forwards)
+		}
+	}
+}
+
+type benchmarkKey int
+
+func (k benchmarkKey) Hash() int {
+	// a java style hash of a pair of ints, typical of real keys
+	return 31*(31+int(k)>>10) + int(k)&1023
+}
+
+func (k benchmarkKey) Equals(other interface{}) bool {
+	o, ok := other.(benchmarkKey)
+	return ok && o == k
+}
+
+const benchmarkMapSize = 1 << 21
+
+var (
+	benchmarkMaps = make(map[bool]Map)
+	benchmarkKeys []Key
+)
+
+func init() {
+	benchmarkKeys = make([]Key, benchmarkMapSize)
+	for i := range benchmarkKeys {
+		benchmarkKeys[i] = benchmarkKey(i)
+	}
+}
+
+func benchmarkMap(openAddressing bool) Map {
+	if m, ok := benchmarkMaps[openAddressing]; true == ok {
+		return m
+	}
+	var m Map
+	if true == openAddressing {
+		m = NewMap(WithOpenAddressing())
+	} else {
+		m = NewMap()
+	}
+	for i := 0; i < benchmarkMapSize; i++ {
+		m.Put(benchmarkKeys[i], i)
+	}
+	benchmarkMaps[openAddressing] = m
+	return m
+}
+
+func benchmarkGet(b *testing.B, openAddressing bool) {
+	m := benchmarkMap(openAddressing)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if i%benchmarkMapSize != m.Get(benchmarkKeys[i%benchmarkMapSize]).(int) {
+			b.Fatal()
+		}
+	}
+}
+
+func benchmarkPut(b *testing.B, openAddressing bool) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if 0 == i%benchmarkMapSize {
+			b.StopTimer()
+			if true == openAddressing {
+				benchmarkMaps[openAddressing] = NewMap(WithOpenAddressing())
+			} else {
+				benchmarkMaps[openAddressing] = NewMap()
+			}
+			b.StartTimer()
+		}
+		benchmarkMaps[openAddressing].Put(benchmarkKeys[i%benchmarkMapSize], i)
+	}
+	delete(benchmarkMaps, openAddressing)
+}
+
+func BenchmarkHashMap_Get(b *testing.B) {
+	benchmarkGet(b, false)
+}
+
+func BenchmarkSwissMap_Get(b *testing.B) {
+	benchmarkGet(b, true)
+}
+
+func BenchmarkHashMap_Put(b *testing.B) {
+	benchmarkPut(b, false)
+}
+
+func BenchmarkSwissMap_Put(b *testing.B) {
+	benchmarkPut(b, true)
+}
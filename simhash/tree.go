@@ -0,0 +1,286 @@
+/*
+   Copyright 2017 Joseph Cumines
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+
+ */
+
+package simhash
+
+// avlTree is a self-balancing binary search tree, ordered using compare, which is used to implement the ordered
+// structures in this package.
+type avlTree struct {
+	root    *avlNode
+	size    int
+	compare func(a, b Key) int
+	// modCount is incremented whenever a key is added or removed.
+	modCount int
+}
+
+type avlNode struct {
+	key    Key
+	value  Value
+	left   *avlNode
+	right  *avlNode
+	height int
+}
+
+func (n *avlNode) Key() Key {
+	return n.key
+}
+
+func (n *avlNode) Value() Value {
+	return n.value
+}
+
+func avlHeight(n *avlNode) int {
+	if nil == n {
+		return 0
+	}
+	return n.height
+}
+
+func (n *avlNode) update() {
+	n.height = avlHeight(n.left)
+	if h := avlHeight(n.right); h > n.height {
+		n.height = h
+	}
+	n.height++
+}
+
+func (n *avlNode) rotateLeft() *avlNode {
+	r := n.right
+	n.right = r.left
+	r.left = n
+	n.update()
+	r.update()
+	return r
+}
+
+func (n *avlNode) rotateRight() *avlNode {
+	l := n.left
+	n.left = l.right
+	l.right = n
+	n.update()
+	l.update()
+	return l
+}
+
+// balance restores the AVL invariant for n, assuming it's children are balanced, returning the new root.
+func (n *avlNode) balance() *avlNode {
+	n.update()
+	switch b := avlHeight(n.left) - avlHeight(n.right); {
+	case b > 1:
+		if avlHeight(n.left.left) < avlHeight(n.left.right) {
+			n.left = n.left.rotateLeft()
+		}
+		return n.rotateRight()
+	case b < -1:
+		if avlHeight(n.right.right) < avlHeight(n.right.left) {
+			n.right = n.right.rotateRight()
+		}
+		return n.rotateLeft()
+	}
+	return n
+}
+
+func newAvlTree(compare func(a, b Key) int) *avlTree {
+	return &avlTree{compare: compare}
+}
+
+// get returns the node for key, or nil.
+func (t *avlTree) get(key Key) *avlNode {
+	n := t.root
+	for nil != n {
+		c := t.compare(key, n.key)
+		switch {
+		case c < 0:
+			n = n.left
+		case c > 0:
+			n = n.right
+		default:
+			return n
+		}
+	}
+	return nil
+}
+
+// put stores value as key, returning any existing value, and true if it existed.
+func (t *avlTree) put(key Key, value Value) (Value, bool) {
+	var old Value
+	existed := false
+	var insert func(n *avlNode) *avlNode
+	insert = func(n *avlNode) *avlNode {
+		if nil == n {
+			t.size++
+			t.modCount++
+			return &avlNode{key: key, value: value, height: 1}
+		}
+		c := t.compare(key, n.key)
+		switch {
+		case c < 0:
+			n.left = insert(n.left)
+		case c > 0:
+			n.right = insert(n.right)
+		default:
+			old, existed = n.value, true
+			n.key, n.value = key, value
+			return n
+		}
+		return n.balance()
+	}
+	t.root = insert(t.root)
+	return old, existed
+}
+
+// remove deletes key from the tree, returning it's value, and true if it existed.
+func (t *avlTree) remove(key Key) (Value, bool) {
+	var old Value
+	existed := false
+	var removeMin func(n *avlNode) (*avlNode, *avlNode)
+	removeMin = func(n *avlNode) (*avlNode, *avlNode) {
+		if nil == n.left {
+			return n.right, n
+		}
+		var min *avlNode
+		n.left, min = removeMin(n.left)
+		return n.balance(), min
+	}
+	var remove func(n *avlNode) *avlNode
+	remove = func(n *avlNode) *avlNode {
+		if nil == n {
+			return nil
+		}
+		c := t.compare(key, n.key)
+		switch {
+		case c < 0:
+			n.left = remove(n.left)
+		case c > 0:
+			n.right = remove(n.right)
+		default:
+			old, existed = n.value, true
+			t.size--
+			t.modCount++
+			if nil == n.left {
+				return n.right
+			}
+			if nil == n.right {
+				return n.left
+			}
+			var min *avlNode
+			n.right, min = removeMin(n.right)
+			min.left, min.right = n.left, n.right
+			n = min
+		}
+		return n.balance()
+	}
+	t.root = remove(t.root)
+	return old, existed
+}
+
+func (t *avlTree) first() *avlNode {
+	n := t.root
+	for nil != n && nil != n.left {
+		n = n.left
+	}
+	return n
+}
+
+func (t *avlTree) last() *avlNode {
+	n := t.root
+	for nil != n && nil != n.right {
+		n = n.right
+	}
+	return n
+}
+
+// search finds the closest node to key, in the direction indicated by less, including an equal node if inclusive
+// is true, e.g. search(key, true, true) finds the floor of key.
+func (t *avlTree) search(key Key, less bool, inclusive bool) *avlNode {
+	var found *avlNode
+	n := t.root
+	for nil != n {
+		c := t.compare(key, n.key)
+		if 0 == c && true == inclusive {
+			return n
+		}
+		if true == less {
+			if c > 0 {
+				found = n
+				n = n.right
+			} else {
+				n = n.left
+			}
+		} else {
+			if c < 0 {
+				found = n
+				n = n.left
+			} else {
+				n = n.right
+			}
+		}
+	}
+	return found
+}
+
+func (t *avlTree) floor(key Key) *avlNode {
+	return t.search(key, true, true)
+}
+
+func (t *avlTree) ceiling(key Key) *avlNode {
+	return t.search(key, false, true)
+}
+
+func (t *avlTree) lower(key Key) *avlNode {
+	return t.search(key, true, false)
+}
+
+func (t *avlTree) higher(key Key) *avlNode {
+	return t.search(key, false, false)
+}
+
+// walk calls fn with each node in order, stopping early if fn returns false.
+func (t *avlTree) walk(fn func(n *avlNode) bool) {
+	var walk func(n *avlNode) bool
+	walk = func(n *avlNode) bool {
+		if nil == n {
+			return true
+		}
+		return walk(n.left) && fn(n) && walk(n.right)
+	}
+	walk(t.root)
+}
+
+// walkRange calls fn with each node in order, that is greater than or equal to from, and less than to, where a nil
+// bound is unbounded, stopping early if fn returns false.
+func (t *avlTree) walkRange(from, to Key, fn func(n *avlNode) bool) {
+	var walk func(n *avlNode) bool
+	walk = func(n *avlNode) bool {
+		if nil == n {
+			return true
+		}
+		aboveFrom := nil == from || t.compare(n.key, from) >= 0
+		belowTo := nil == to || t.compare(n.key, to) < 0
+		if true == aboveFrom && false == walk(n.left) {
+			return false
+		}
+		if true == aboveFrom && true == belowTo && false == fn(n) {
+			return false
+		}
+		if true == belowTo {
+			return walk(n.right)
+		}
+		return true
+	}
+	walk(t.root)
+}
@@ -0,0 +1,141 @@
+/*
+   Copyright 2017 Joseph Cumines
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+
+ */
+
+package simhash
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// verify checks the ordering and balance of the tree, returning the height.
+func (n *avlNode) verify(t *testing.T, tree *avlTree) int {
+	if nil == n {
+		return 0
+	}
+	if nil != n.left && tree.compare(n.left.key, n.key) >= 0 {
+		t.Fatal("left child out of order")
+	}
+	if nil != n.right && tree.compare(n.right.key, n.key) <= 0 {
+		t.Fatal("right child out of order")
+	}
+	l, r := n.left.verify(t, tree), n.right.verify(t, tree)
+	if l-r > 1 || r-l > 1 {
+		t.Fatal("unbalanced")
+	}
+	h := l
+	if r > h {
+		h = r
+	}
+	if h+1 != n.height {
+		t.Fatal("bad height")
+	}
+	return n.height
+}
+
+func TestAvlTree_random(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	tree := newAvlTree(compareKeys)
+	model := make(map[int]int)
+	for x := 0; x < 5000; x++ {
+		k := r.Intn(300)
+		if 0 == r.Intn(3) {
+			v, ok := tree.remove(testKeyInt(k))
+			mv, mok := model[k]
+			if ok != mok || (true == ok && v.(int) != mv) {
+				t.Fatal(k, v, ok)
+			}
+			delete(model, k)
+		} else {
+			v, ok := tree.put(testKeyInt(k), x)
+			mv, mok := model[k]
+			if ok != mok || (true == ok && v.(int) != mv) {
+				t.Fatal(k, v, ok)
+			}
+			model[k] = x
+		}
+		if len(model) != tree.size {
+			t.Fatal(len(model), tree.size)
+		}
+		tree.root.verify(t, tree)
+	}
+	for k, v := range model {
+		if n := tree.get(testKeyInt(k)); nil == n || v != n.value.(int) {
+			t.Fatal(k)
+		}
+	}
+	count := 0
+	prev := -1
+	tree.walk(func(n *avlNode) bool {
+		if int(n.key.(testKeyInt)) <= prev {
+			t.Fatal("out of order")
+		}
+		prev = int(n.key.(testKeyInt))
+		count++
+		return true
+	})
+	if count != len(model) {
+		t.Fatal(count)
+	}
+}
+
+func TestAvlTree_search(t *testing.T) {
+	tree := newAvlTree(compareKeys)
+	if nil != tree.first() || nil != tree.last() || nil != tree.floor(testKeyInt(1)) || nil != tree.higher(testKeyInt(1)) {
+		t.Fatal()
+	}
+	for _, k := range []int{10, 20, 30, 40, 50} {
+		tree.put(testKeyInt(k), k)
+	}
+	key := func(n *avlNode) int {
+		if nil == n {
+			return -1
+		}
+		return int(n.key.(testKeyInt))
+	}
+	if 10 != key(tree.first()) || 50 != key(tree.last()) {
+		t.Fatal()
+	}
+	if 20 != key(tree.floor(testKeyInt(20))) || 20 != key(tree.floor(testKeyInt(25))) || -1 != key(tree.floor(testKeyInt(5))) {
+		t.Fatal()
+	}
+	if 20 != key(tree.ceiling(testKeyInt(20))) || 30 != key(tree.ceiling(testKeyInt(25))) || -1 != key(tree.ceiling(testKeyInt(55))) {
+		t.Fatal()
+	}
+	if 10 != key(tree.lower(testKeyInt(20))) || 20 != key(tree.lower(testKeyInt(25))) || -1 != key(tree.lower(testKeyInt(10))) {
+		t.Fatal()
+	}
+	if 30 != key(tree.higher(testKeyInt(20))) || 30 != key(tree.higher(testKeyInt(25))) || -1 != key(tree.higher(testKeyInt(50))) {
+		t.Fatal()
+	}
+	keys := make([]int, 0)
+	tree.walkRange(testKeyInt(20), testKeyInt(40), func(n *avlNode) bool {
+		keys = append(keys, key(n))
+		return true
+	})
+	if false == equalInts([]int{20, 30}, keys) {
+		t.Fatal(keys)
+	}
+	keys = keys[:0]
+	tree.walkRange(testKeyInt(15), nil, func(n *avlNode) bool {
+		keys = append(keys, key(n))
+		return 40 != key(n)
+	})
+	if false == equalInts([]int{20, 30, 40}, keys) {
+		t.Fatal(keys)
+	}
+}
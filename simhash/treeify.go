@@ -0,0 +1,124 @@
+/*
+   Copyright 2017 Joseph Cumines
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+
+ */
+
+package simhash
+
+import (
+	"reflect"
+)
+
+const (
+	// treeifyThreshold is the bucket size at which a hashMap will attempt to index the bucket with a tree.
+	treeifyThreshold = 8
+	// untreeifyThreshold is the bucket size at which a hashMap will discard the tree for a bucket.
+	untreeifyThreshold = 6
+)
+
+// bucketTree indexes a large bucket of a hashMap, mapping each key to it's index in the bucket, which keeps lookups
+// logarithmic in the size of the bucket, the same as the tree bins in Java 8's HashMap. It may only be used when
+// every key in the bucket is Comparable, and of the same type (as Compare is only expected to handle it's own type).
+type bucketTree struct {
+	t       *avlTree
+	keyType reflect.Type
+}
+
+// accepts returns true if key may be looked up or stored in the tree.
+func (b *bucketTree) accepts(key Key) bool {
+	return nil != key && reflect.TypeOf(key) == b.keyType
+}
+
+// treeify attempts to build a tree for the bucket with hash h, which will fail if the keys are not all Comparable, of
+// the same type. A failure is recorded as a nil tree, so it won't be attempted again until the bucket shrinks below
+// untreeifyThreshold, as otherwise a single key of another type could force every insert to rebuild the tree.
+func (m *hashMap) treeify(h int) {
+	if nil == m.trees {
+		m.trees = make(map[int]*bucketTree)
+	}
+	m.trees[h] = nil
+	pairs := m.m[h]
+	if nil == pairs[0] || nil == pairs[0].Key() || false == isComparable(pairs[0].Key()) {
+		return
+	}
+	b := &bucketTree{newAvlTree(compareKeys), reflect.TypeOf(pairs[0].Key())}
+	for i, pair := range pairs {
+		if nil == pair || false == b.accepts(pair.Key()) {
+			return
+		}
+		b.t.put(pair.Key(), i)
+	}
+	m.trees[h] = b
+}
+
+// treeFind finds key using the tree for the bucket with hash h, if there is one, and it can be used.
+func (m *hashMap) treeFind(h int, key Key) (i int, ok bool, used bool) {
+	b := m.trees[h]
+	if nil == b || false == b.accepts(key) {
+		return 0, false, false
+	}
+	if n := b.t.get(key); nil != n {
+		return n.value.(int), true, true
+	}
+	return 0, false, true
+}
+
+// treeInsert updates the tree (if any) after pair was appended to the bucket with hash h, or attempts to create one.
+func (m *hashMap) treeInsert(h int, pair Pair) {
+	b, ok := m.trees[h]
+	if false == ok {
+		if len(m.m[h]) >= treeifyThreshold {
+			m.treeify(h)
+		}
+		return
+	}
+	if nil == b {
+		return
+	}
+	if false == b.accepts(pair.Key()) {
+		m.trees[h] = nil
+		return
+	}
+	b.t.put(pair.Key(), len(m.m[h])-1)
+}
+
+// treeReplace discards the tree (if any) for the bucket with hash h, if pair can't be stored in it, after it
+// replaced a pair with an equal key.
+func (m *hashMap) treeReplace(h int, pair Pair) {
+	if b := m.trees[h]; nil != b && false == b.accepts(pair.Key()) {
+		m.trees[h] = nil
+	}
+}
+
+// treeRemove updates the tree (if any) before the pair at index i is removed from the bucket with hash h, by
+// swapping it with the last pair.
+func (m *hashMap) treeRemove(h int, i int) {
+	b, ok := m.trees[h]
+	if false == ok {
+		return
+	}
+	pairs := m.m[h]
+	if len(pairs)-1 < untreeifyThreshold {
+		delete(m.trees, h)
+		return
+	}
+	if nil == b {
+		return
+	}
+	b.t.remove(pairs[i].Key())
+	if last := len(pairs) - 1; i != last {
+		b.t.put(pairs[last].Key(), i)
+	}
+}
@@ -0,0 +1,184 @@
+/*
+   Copyright 2017 Joseph Cumines
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+
+ */
+
+package simhash
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// verifyTree checks that the tree for the bucket with hash h indexes every pair correctly.
+func (m *hashMap) verifyTree(t *testing.T, h int) {
+	t.Helper()
+	b := m.trees[h]
+	if nil == b {
+		t.Fatal("no tree")
+	}
+	if b.t.size != len(m.m[h]) {
+		t.Fatal(b.t.size, len(m.m[h]))
+	}
+	b.t.walk(func(n *avlNode) bool {
+		if false == n.key.Equals(m.m[h][n.value.(int)].Key()) {
+			t.Fatal("bad index", n.key, n.value)
+		}
+		return true
+	})
+}
+
+func TestHashMap_treeify(t *testing.T) {
+	m := NewMap(WithoutHashSeed()).(*hashMap)
+	for i := 0; i < treeifyThreshold-1; i++ {
+		m.Put(testKeyCollision(i), i)
+	}
+	if nil != m.trees[7] {
+		t.Fatal()
+	}
+	m.Put(testKeyCollision(treeifyThreshold-1), treeifyThreshold-1)
+	m.verifyTree(t, 7)
+	for i := treeifyThreshold; i < 100; i++ {
+		m.Put(testKeyCollision(i), i)
+	}
+	m.verifyTree(t, 7)
+	for i := 0; i < 100; i++ {
+		if i != m.Get(testKeyCollision(i)).(int) {
+			t.Fatal(i)
+		}
+	}
+	if true == m.Contains(testKeyCollision(100)) || 100 != m.Size() {
+		t.Fatal()
+	}
+	// replacing values does not affect the tree
+	if 5 != m.Put(testKeyCollision(5), 55).(int) || 55 != m.Get(testKeyCollision(5)).(int) {
+		t.Fatal()
+	}
+	m.verifyTree(t, 7)
+	// remove from the front, so that pairs are swapped into the removed positions
+	for i := 0; i < 100-untreeifyThreshold; i++ {
+		if nil == m.Remove(testKeyCollision(i)) {
+			t.Fatal(i)
+		}
+		m.verifyTree(t, 7)
+	}
+	m.Remove(testKeyCollision(100 - untreeifyThreshold))
+	if nil != m.trees[7] || untreeifyThreshold-1 != m.Size() || 99 != m.Get(testKeyCollision(99)).(int) {
+		t.Fatal()
+	}
+}
+
+func TestHashMap_treeify_mixedKeys(t *testing.T) {
+	m := NewMap(WithoutHashSeed()).(*hashMap)
+	for i := 0; i < 20; i++ {
+		m.Put(testKeyCollision(i), i)
+	}
+	m.verifyTree(t, 7)
+	// a key of a different type discards the tree
+	m.Put(testKeyCollisionString("seven"), "seven")
+	if nil != m.trees[7] || "seven" != m.Get(testKeyCollisionString("seven")).(string) || 10 != m.Get(testKeyCollision(10)).(int) {
+		t.Fatal()
+	}
+	// which won't be rebuilt, while it is in the bucket
+	m.Put(testKeyCollision(20), 20)
+	if nil != m.trees[7] {
+		t.Fatal()
+	}
+	// or until the bucket shrinks below untreeifyThreshold
+	m.Remove(testKeyCollisionString("seven"))
+	m.Put(testKeyCollision(21), 21)
+	if tree, ok := m.trees[7]; nil != tree || false == ok {
+		t.Fatal()
+	}
+	for i := 0; i < 22-untreeifyThreshold+1; i++ {
+		m.Remove(testKeyCollision(i))
+	}
+	if _, ok := m.trees[7]; true == ok {
+		t.Fatal()
+	}
+	for i := 0; i < 22-untreeifyThreshold+1; i++ {
+		m.Put(testKeyCollision(i), i)
+	}
+	m.verifyTree(t, 7)
+	// neither are keys that aren't Comparable
+	m = NewMap(WithoutHashSeed()).(*hashMap)
+	for i := 0; i < 20; i++ {
+		m.Put(testKeyCollisionString(rune('a'+i)), i)
+	}
+	if nil != m.trees[7] || 20 != m.Size() || 10 != m.Get(testKeyCollisionString("k")).(int) {
+		t.Fatal()
+	}
+}
+
+func TestHashMap_treeify_poisoned(t *testing.T) {
+	m := NewMap(WithoutHashSeed()).(*hashMap)
+	for i := 0; i < 2000; i++ {
+		m.Put(testKeyCollision(i), i)
+	}
+	m.verifyTree(t, 7)
+	// a single key of another type must not cause every insert to attempt to rebuild the tree
+	m.Put(testKeyCollisionString("poison"), 0)
+	i := 2000
+	allocs := testing.AllocsPerRun(100, func() {
+		m.Put(testKeyCollision(i), i)
+		i++
+	})
+	if allocs > 5 {
+		t.Fatal(allocs)
+	}
+	if tree, ok := m.trees[7]; nil != tree || false == ok || i+1 != m.Size() || 2050 != m.Get(testKeyCollision(2050)).(int) {
+		t.Fatal()
+	}
+}
+
+func TestHashMap_treeify_random(t *testing.T) {
+	r := rand.New(rand.NewSource(4))
+	m := NewMap(WithoutHashSeed()).(*hashMap)
+	model := make(map[int]int)
+	for x := 0; x < 10000; x++ {
+		k := r.Intn(40)
+		if 0 == r.Intn(2) {
+			m.Remove(testKeyCollision(k))
+			delete(model, k)
+		} else {
+			m.Put(testKeyCollision(k), x)
+			model[k] = x
+		}
+		if len(model) >= treeifyThreshold {
+			m.verifyTree(t, 7)
+		}
+		if len(model) < untreeifyThreshold && nil != m.trees[7] {
+			t.Fatal("not untreeified")
+		}
+		for k, v := range model {
+			if v != m.Get(testKeyCollision(k)).(int) {
+				t.Fatal(k)
+			}
+		}
+	}
+}
+
+func BenchmarkHashMap_Get_collisions(b *testing.B) {
+	m := NewMap()
+	keys := make([]Key, 10000)
+	for i := range keys {
+		keys[i] = testKeyCollision(i)
+		m.Put(keys[i], i)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m.Get(keys[i%len(keys)])
+	}
+}